@@ -1,9 +1,20 @@
 package commands
 
+import "backend/domain"
+
 type CrawlCommand struct {
-	URL string
+	URL     string
+	Options domain.CrawlOptions
 }
 
 type DeleteCrawlResultsCommand struct {
 	IDs []int
 }
+
+type CancelCrawlsCommand struct {
+	IDs []int
+}
+
+type RequeueCrawlsCommand struct {
+	IDs []int
+}
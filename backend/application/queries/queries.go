@@ -1,10 +1,24 @@
 package queries
 
+import "backend/domain"
+
 type GetTestMessageQuery struct {}
 
 type GetCrawlResultsQuery struct {
-	CurrPage   int
-	PageSize   int
-	Query      string
-	SortingJson string // Added SortingJson
-}
\ No newline at end of file
+	CurrPage  int
+	PageSize  int
+	Query     string
+	Search    string             // Full-text search over the page content; results are ranked by relevance unless sorted
+	Sorting   []domain.SortField // Applied in order; empty sorts by newest first
+	Filter    domain.CrawlResultFilter
+	UseCursor bool   // Keyset paging by Cursor instead of offset paging by CurrPage
+	Cursor    string // Opaque cursor of a previous response; empty for the first page
+	Count     string // exact, estimated or none; defaults to exact for offset paging and none for keyset paging
+}
+
+type GetCrawlLinksQuery struct {
+	CrawlResultID int
+	CurrPage      int
+	PageSize      int
+	Filter        domain.LinkFilter
+}
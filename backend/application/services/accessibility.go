@@ -0,0 +1,191 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"backend/domain"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// Accessibility rule IDs
+const (
+	RuleHeadingOrder = "heading-order"
+	RuleHTMLHasLang  = "html-has-lang"
+	RuleLabel        = "label"
+	RuleLinkName     = "link-name"
+	RuleButtonName   = "button-name"
+	RuleDuplicateID  = "duplicate-id"
+	RuleLandmarkMain = "landmark-one-main"
+	RuleTableHeaders = "table-has-header"
+)
+
+// auditAccessibility runs static WCAG-oriented rules on the document and its heading outline
+func auditAccessibility(doc *goquery.Document, outline HeadingOutline) []domain.AccessibilityIssue {
+	var issues []domain.AccessibilityIssue
+	report := func(rule, severity string, element *goquery.Selection, format string, args ...any) {
+		issues = append(issues, domain.AccessibilityIssue{
+			RuleID:   rule,
+			Severity: severity,
+			Message:  fmt.Sprintf(format, args...),
+			Selector: cssPath(element),
+		})
+	}
+
+	// Headings must not skip levels on the way down, e.g. h2 followed by h4
+	previousLevel := 0
+	for i, heading := range outline.Headings {
+		if previousLevel > 0 && heading.Level > previousLevel+1 {
+			report(RuleHeadingOrder, domain.SeverityWarning, outline.elements[i], "h%d follows h%d, skipping a heading level", heading.Level, previousLevel)
+		}
+		previousLevel = heading.Level
+	}
+
+	htmlElement := doc.Find("html").First()
+	if strings.TrimSpace(htmlElement.AttrOr("lang", "")) == "" {
+		report(RuleHTMLHasLang, domain.SeverityError, htmlElement, "<html> has no lang attribute")
+	}
+
+	doc.Find("input, select, textarea").Each(func(i int, field *goquery.Selection) {
+		switch strings.ToLower(field.AttrOr("type", "")) {
+		case "hidden", "submit", "button", "reset", "image":
+			return
+		}
+		if !hasLabel(doc, field) {
+			report(RuleLabel, domain.SeverityError, field, "<%s> has no label", goquery.NodeName(field))
+		}
+	})
+
+	doc.Find("a[href]").Each(func(i int, link *goquery.Selection) {
+		if accessibleName(link) == "" {
+			report(RuleLinkName, domain.SeverityError, link, "Link has no discernible text")
+		}
+	})
+
+	doc.Find(`button, input[type="button" i], input[type="submit" i], input[type="reset" i]`).Each(func(i int, button *goquery.Selection) {
+		name := accessibleName(button)
+		if goquery.NodeName(button) == "input" {
+			// Submit and reset buttons have a default label
+			if inputType := strings.ToLower(button.AttrOr("type", "")); inputType == "submit" || inputType == "reset" {
+				return
+			}
+			if value := strings.TrimSpace(button.AttrOr("value", "")); value != "" {
+				name = value
+			}
+		}
+		if name == "" {
+			report(RuleButtonName, domain.SeverityError, button, "Button has no discernible text")
+		}
+	})
+
+	seenIDs := make(map[string]bool)
+	reportedIDs := make(map[string]bool)
+	doc.Find("[id]").Each(func(i int, element *goquery.Selection) {
+		id := element.AttrOr("id", "")
+		if id == "" {
+			return
+		}
+		if seenIDs[id] && !reportedIDs[id] {
+			reportedIDs[id] = true
+			report(RuleDuplicateID, domain.SeverityWarning, element, "id %q is used more than once", id)
+		}
+		seenIDs[id] = true
+	})
+
+	if doc.Find(`main, [role="main" i]`).Length() == 0 {
+		report(RuleLandmarkMain, domain.SeverityWarning, doc.Find("body").First(), "Page has no main landmark")
+	}
+
+	doc.Find("table").Each(func(i int, table *goquery.Selection) {
+		switch strings.ToLower(table.AttrOr("role", "")) {
+		case "presentation", "none":
+			return
+		}
+		if table.Find(`th, [role="columnheader" i], [role="rowheader" i]`).Length() == 0 {
+			report(RuleTableHeaders, domain.SeverityWarning, table, "Table has no header cells")
+		}
+	})
+
+	return issues
+}
+
+// hasLabel reports whether a form field has a label element, an ARIA label or a title
+func hasLabel(doc *goquery.Document, field *goquery.Selection) bool {
+	if strings.TrimSpace(field.AttrOr("aria-label", "")) != "" ||
+		strings.TrimSpace(field.AttrOr("aria-labelledby", "")) != "" ||
+		strings.TrimSpace(field.AttrOr("title", "")) != "" {
+		return true
+	}
+	if field.ParentsFiltered("label").Length() > 0 {
+		return true
+	}
+	if id := field.AttrOr("id", ""); id != "" {
+		found := false
+		doc.Find("label[for]").EachWithBreak(func(i int, label *goquery.Selection) bool {
+			found = label.AttrOr("for", "") == id
+			return !found
+		})
+		return found
+	}
+	return false
+}
+
+// accessibleName approximates the accessible name of a link or button
+func accessibleName(element *goquery.Selection) string {
+	if label := strings.TrimSpace(element.AttrOr("aria-label", "")); label != "" {
+		return label
+	}
+	if strings.TrimSpace(element.AttrOr("aria-labelledby", "")) != "" {
+		return element.AttrOr("aria-labelledby", "")
+	}
+	if text := strings.TrimSpace(element.Text()); text != "" {
+		return text
+	}
+	if alt := strings.TrimSpace(element.Find("img[alt]").First().AttrOr("alt", "")); alt != "" {
+		return alt
+	}
+	return strings.TrimSpace(element.AttrOr("title", ""))
+}
+
+// cssPath builds a CSS selector that locates the element, anchored at the closest ancestor with an ID
+func cssPath(element *goquery.Selection) string {
+	if element.Length() == 0 {
+		return ""
+	}
+
+	var parts []string
+	for node := element.Get(0); node != nil && node.Type == html.ElementNode && node.Parent != nil; node = node.Parent {
+		for _, attr := range node.Attr {
+			if attr.Key == "id" && attr.Val != "" && !strings.ContainsAny(attr.Val, " \t\n\"'#.:[]") {
+				parts = append(parts, node.Data+"#"+attr.Val)
+				return joinReversed(parts)
+			}
+		}
+
+		// Position among siblings of the same tag
+		position, total := 0, 0
+		for sibling := node.Parent.FirstChild; sibling != nil; sibling = sibling.NextSibling {
+			if sibling.Type == html.ElementNode && sibling.Data == node.Data {
+				total++
+				if sibling == node {
+					position = total
+				}
+			}
+		}
+		if total > 1 {
+			parts = append(parts, fmt.Sprintf("%s:nth-of-type(%d)", node.Data, position))
+		} else {
+			parts = append(parts, node.Data)
+		}
+	}
+	return joinReversed(parts)
+}
+
+func joinReversed(parts []string) string {
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, " > ")
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"backend/domain"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Page is a fetched and parsed page handed to every analyzer
+type Page struct {
+	URL      *url.URL
+	Response *http.Response // Body has already been read into Body
+	Body     []byte         // Transcoded to UTF-8
+	Document *goquery.Document
+	Options  domain.CrawlOptions
+
+	headingOutline *HeadingOutline
+}
+
+// HeadingOutline returns the heading outline of the document, building it on first use so analyzers share it
+func (p *Page) HeadingOutline() HeadingOutline {
+	if p.headingOutline == nil {
+		outline := buildHeadingOutline(p.Document)
+		p.headingOutline = &outline
+	}
+	return *p.headingOutline
+}
+
+// Analyzer extracts information from a crawled page and contributes it to the CrawlResult
+type Analyzer interface {
+	// Name identifies the analyzer in crawl requests
+	Name() string
+	Analyze(ctx context.Context, page *Page, result *domain.CrawlResult) error
+}
+
+// AnalyzerRegistry holds the analyzers available to crawls, in the order they run
+type AnalyzerRegistry struct {
+	analyzers []Analyzer
+	byName    map[string]Analyzer
+}
+
+// NewAnalyzerRegistry creates a new AnalyzerRegistry with the given analyzers registered
+func NewAnalyzerRegistry(analyzers ...Analyzer) (*AnalyzerRegistry, error) {
+	r := &AnalyzerRegistry{byName: make(map[string]Analyzer)}
+	for _, a := range analyzers {
+		if err := r.Register(a); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Register adds an analyzer; it runs after all previously registered ones
+func (r *AnalyzerRegistry) Register(a Analyzer) error {
+	if _, exists := r.byName[a.Name()]; exists {
+		return fmt.Errorf("analyzer %q is already registered", a.Name())
+	}
+	r.analyzers = append(r.analyzers, a)
+	r.byName[a.Name()] = a
+	return nil
+}
+
+// Names returns the names of all registered analyzers in run order
+func (r *AnalyzerRegistry) Names() []string {
+	names := make([]string, len(r.analyzers))
+	for i, a := range r.analyzers {
+		names[i] = a.Name()
+	}
+	return names
+}
+
+// Select returns the analyzers to run for a crawl. An empty enabled list means all analyzers;
+// analyzers named in disabled are removed from the selection.
+func (r *AnalyzerRegistry) Select(enabled, disabled []string) ([]Analyzer, error) {
+	include := make(map[string]bool)
+	for _, name := range enabled {
+		if _, ok := r.byName[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", domain.ErrUnknownAnalyzer, name)
+		}
+		include[name] = true
+	}
+
+	exclude := make(map[string]bool)
+	for _, name := range disabled {
+		if _, ok := r.byName[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", domain.ErrUnknownAnalyzer, name)
+		}
+		exclude[name] = true
+	}
+
+	var selected []Analyzer
+	for _, a := range r.analyzers {
+		if len(include) > 0 && !include[a.Name()] {
+			continue
+		}
+		if exclude[a.Name()] {
+			continue
+		}
+		selected = append(selected, a)
+	}
+	return selected, nil
+}
+
+// containsAnalyzer reports whether the analyzer with the given name is among the analyzers
+func containsAnalyzer(analyzers []Analyzer, name string) bool {
+	for _, a := range analyzers {
+		if a.Name() == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,343 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"backend/domain"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Names of the built-in analyzers
+const (
+	AnalyzerHTMLVersion    = "html_version"
+	AnalyzerTitle          = "title"
+	AnalyzerHeadings       = "headings"
+	AnalyzerLoginForm      = "login_form"
+	AnalyzerLinks          = "links"
+	AnalyzerSEOMetadata    = "seo_metadata"
+	AnalyzerStructuredData = "structured_data"
+	AnalyzerImages         = "images"
+	AnalyzerAccessibility  = "accessibility"
+	AnalyzerSecurity       = "security"
+	AnalyzerMixedContent   = "mixed_content"
+	AnalyzerVisibleText    = "visible_text"
+)
+
+// HTMLVersionAnalyzer detects the HTML version from the DOCTYPE
+type HTMLVersionAnalyzer struct{}
+
+func NewHTMLVersionAnalyzer() *HTMLVersionAnalyzer {
+	return &HTMLVersionAnalyzer{}
+}
+
+func (a *HTMLVersionAnalyzer) Name() string { return AnalyzerHTMLVersion }
+
+func (a *HTMLVersionAnalyzer) Analyze(ctx context.Context, page *Page, result *domain.CrawlResult) error {
+	versionInfo := detectHTMLVersion(page.Body)
+	result.HTMLVersion = versionInfo.Version
+	result.HasDoctype = versionInfo.HasDoctype
+	result.DocumentMode = versionInfo.DocumentMode
+	return nil
+}
+
+// TitleAnalyzer extracts the page title
+type TitleAnalyzer struct{}
+
+func NewTitleAnalyzer() *TitleAnalyzer {
+	return &TitleAnalyzer{}
+}
+
+func (a *TitleAnalyzer) Name() string { return AnalyzerTitle }
+
+func (a *TitleAnalyzer) Analyze(ctx context.Context, page *Page, result *domain.CrawlResult) error {
+	result.PageTitle = page.Document.Find("title").Text()
+	if result.PageTitle == "" {
+		result.PageTitle = "NO TITLE"
+	}
+	return nil
+}
+
+// HeadingsAnalyzer builds the heading outline and counts the h1..h6 heading tags
+type HeadingsAnalyzer struct{}
+
+func NewHeadingsAnalyzer() *HeadingsAnalyzer {
+	return &HeadingsAnalyzer{}
+}
+
+func (a *HeadingsAnalyzer) Name() string { return AnalyzerHeadings }
+
+func (a *HeadingsAnalyzer) Analyze(ctx context.Context, page *Page, result *domain.CrawlResult) error {
+	outline := page.HeadingOutline()
+	for heading, count := range outline.Counts {
+		result.HeadingCounts[heading] = count
+	}
+	result.Headings = outline.Headings
+	result.MultipleH1 = outline.MultipleH1
+	result.MissingH1 = outline.MissingH1
+	result.HeadingLevelSkips = outline.LevelSkips
+	return nil
+}
+
+// LoginFormAnalyzer detects whether the page contains a login form
+type LoginFormAnalyzer struct{}
+
+func NewLoginFormAnalyzer() *LoginFormAnalyzer {
+	return &LoginFormAnalyzer{}
+}
+
+func (a *LoginFormAnalyzer) Name() string { return AnalyzerLoginForm }
+
+func (a *LoginFormAnalyzer) Analyze(ctx context.Context, page *Page, result *domain.CrawlResult) error {
+	loginForm := detectLoginForm(page.Document, page.URL)
+	result.HasLoginForm = loginForm.Found
+	result.LoginFormConfidence = loginForm.Confidence
+	if loginForm.Found {
+		result.LoginFormAction = loginForm.ActionURL
+	}
+	return nil
+}
+
+// SEOMetadataAnalyzer extracts meta tags, canonical URL, Open Graph and Twitter Card properties
+type SEOMetadataAnalyzer struct{}
+
+func NewSEOMetadataAnalyzer() *SEOMetadataAnalyzer {
+	return &SEOMetadataAnalyzer{}
+}
+
+func (a *SEOMetadataAnalyzer) Name() string { return AnalyzerSEOMetadata }
+
+func (a *SEOMetadataAnalyzer) Analyze(ctx context.Context, page *Page, result *domain.CrawlResult) error {
+	metadata := extractSEOMetadata(page.Document, page.URL)
+	result.MetaDescription = metadata.Description
+	result.MetaKeywords = metadata.Keywords
+	result.CanonicalURL = metadata.CanonicalURL
+	result.MetaRobots = metadata.Robots
+	result.Viewport = metadata.Viewport
+	result.Language = metadata.Language
+	result.OpenGraph = metadata.OpenGraph
+	result.TwitterCard = metadata.TwitterCard
+	result.FaviconURL = metadata.FaviconURL
+	return nil
+}
+
+// StructuredDataAnalyzer extracts JSON-LD, Microdata and RDFa entities
+type StructuredDataAnalyzer struct{}
+
+func NewStructuredDataAnalyzer() *StructuredDataAnalyzer {
+	return &StructuredDataAnalyzer{}
+}
+
+func (a *StructuredDataAnalyzer) Name() string { return AnalyzerStructuredData }
+
+func (a *StructuredDataAnalyzer) Analyze(ctx context.Context, page *Page, result *domain.CrawlResult) error {
+	result.StructuredData, result.StructuredDataIssues = extractStructuredData(page.Document, page.URL)
+	return nil
+}
+
+// LinksAnalyzer counts internal and external links, records them in the link inventory and checks them for reachability
+type LinksAnalyzer struct {
+	linkChecker *LinkChecker
+}
+
+func NewLinksAnalyzer(linkChecker *LinkChecker) *LinksAnalyzer {
+	return &LinksAnalyzer{linkChecker: linkChecker}
+}
+
+func (a *LinksAnalyzer) Name() string { return AnalyzerLinks }
+
+func (a *LinksAnalyzer) Analyze(ctx context.Context, page *Page, result *domain.CrawlResult) error {
+	seenLinks := make(map[string]bool)
+	var checkLinks []*url.URL
+	page.Document.Find("a").Each(func(i int, s *goquery.Selection) {
+		link, exists := s.Attr("href")
+		if !exists {
+			return
+		}
+		resolvedLink, err := page.URL.Parse(link)
+		if err != nil {
+			result.InaccessibleLinkCount++
+			return
+		}
+		result.Links = append(result.Links, inventoryLink(s, link, resolvedLink, page.URL))
+		internal := strings.EqualFold(resolvedLink.Hostname(), page.URL.Hostname())
+		if internal {
+			result.InternalLinkCount++
+		} else {
+			result.ExternalLinkCount++
+		}
+
+		// Only http(s) links can be checked or followed; fragments point to the same document
+		if resolvedLink.Scheme != "http" && resolvedLink.Scheme != "https" {
+			return
+		}
+		resolvedLink.Fragment = ""
+		normalized := normalizeURL(resolvedLink)
+		if !seenLinks[normalized] {
+			seenLinks[normalized] = true
+			checkLinks = append(checkLinks, resolvedLink)
+			if internal {
+				result.InternalLinks = append(result.InternalLinks, normalized)
+			}
+		}
+	})
+
+	// Check links for reachability
+	result.BrokenLinks = a.linkChecker.Check(ctx, checkLinks, page.Options.Fetch)
+	result.InaccessibleLinkCount += len(result.BrokenLinks)
+	return nil
+}
+
+// ImagesAnalyzer inventories the images of the page, flags alt text and dimension problems and checks that they load
+type ImagesAnalyzer struct {
+	linkChecker *LinkChecker
+}
+
+func NewImagesAnalyzer(linkChecker *LinkChecker) *ImagesAnalyzer {
+	return &ImagesAnalyzer{linkChecker: linkChecker}
+}
+
+func (a *ImagesAnalyzer) Name() string { return AnalyzerImages }
+
+func (a *ImagesAnalyzer) Analyze(ctx context.Context, page *Page, result *domain.CrawlResult) error {
+	audit := auditImages(page.Document, page.URL)
+	result.ImageCount = audit.ImageCount
+	result.ImagesMissingAltCount = audit.MissingAlt
+	result.ImagesEmptyAltCount = audit.EmptyAlt
+	result.ImagesMissingDimensionsCount = audit.MissingDimensions
+
+	// Request every distinct http(s) image once; inline data: images always load
+	seen := make(map[string]bool)
+	var checkURLs []*url.URL
+	for _, image := range audit.Images {
+		imageURL, err := url.Parse(image.URL)
+		if err != nil || (imageURL.Scheme != "http" && imageURL.Scheme != "https") || seen[image.URL] {
+			continue
+		}
+		seen[image.URL] = true
+		checkURLs = append(checkURLs, imageURL)
+	}
+
+	statuses := make(map[string]ResourceStatus)
+	for _, status := range a.linkChecker.Probe(ctx, checkURLs, page.Options.Fetch, true) {
+		statuses[status.URL.String()] = status
+	}
+
+	brokenURLs := make(map[string]bool)
+	for i := range audit.Images {
+		image := &audit.Images[i]
+		if status, checked := statuses[image.URL]; checked {
+			image.StatusCode = status.StatusCode
+			image.ByteSize = status.ByteSize
+			image.Broken = status.Broken()
+			if status.Err != nil {
+				image.Error = status.Err.Error()
+			} else if image.Broken {
+				image.Error = http.StatusText(status.StatusCode)
+			}
+		}
+		if image.Broken {
+			brokenURLs[image.URL] = true
+		}
+	}
+	result.BrokenImageCount = len(brokenURLs)
+	result.Images = audit.Images
+	return nil
+}
+
+// AccessibilityAnalyzer runs static WCAG-oriented rules on the page
+type AccessibilityAnalyzer struct{}
+
+func NewAccessibilityAnalyzer() *AccessibilityAnalyzer {
+	return &AccessibilityAnalyzer{}
+}
+
+func (a *AccessibilityAnalyzer) Name() string { return AnalyzerAccessibility }
+
+func (a *AccessibilityAnalyzer) Analyze(ctx context.Context, page *Page, result *domain.CrawlResult) error {
+	result.AccessibilityIssues = auditAccessibility(page.Document, page.HeadingOutline())
+	for _, issue := range result.AccessibilityIssues {
+		switch issue.Severity {
+		case domain.SeverityError:
+			result.AccessibilityErrorCount++
+		case domain.SeverityWarning:
+			result.AccessibilityWarningCount++
+		}
+	}
+	return nil
+}
+
+// SecurityAnalyzer audits the TLS certificate chain and the security headers and cookies of the response
+type SecurityAnalyzer struct{}
+
+func NewSecurityAnalyzer() *SecurityAnalyzer {
+	return &SecurityAnalyzer{}
+}
+
+func (a *SecurityAnalyzer) Name() string { return AnalyzerSecurity }
+
+func (a *SecurityAnalyzer) Analyze(ctx context.Context, page *Page, result *domain.CrawlResult) error {
+	report := auditSecurity(page.Response, time.Now())
+	result.SecurityReport = &report
+	result.SecurityScore = &report.Score
+	result.SecurityGrade = report.Grade
+	return nil
+}
+
+// MixedContentAnalyzer finds subresources and form targets of HTTPS pages that use plain HTTP
+type MixedContentAnalyzer struct{}
+
+func NewMixedContentAnalyzer() *MixedContentAnalyzer {
+	return &MixedContentAnalyzer{}
+}
+
+func (a *MixedContentAnalyzer) Name() string { return AnalyzerMixedContent }
+
+func (a *MixedContentAnalyzer) Analyze(ctx context.Context, page *Page, result *domain.CrawlResult) error {
+	result.MixedContent = detectMixedContent(page.Document, page.URL)
+	for _, item := range result.MixedContent {
+		switch item.Type {
+		case domain.MixedContentActive:
+			result.ActiveMixedContentCount++
+		case domain.MixedContentPassive:
+			result.PassiveMixedContentCount++
+		}
+	}
+	return nil
+}
+
+// VisibleTextAnalyzer extracts the text a visitor sees on the page for the full-text search index
+type VisibleTextAnalyzer struct{}
+
+func NewVisibleTextAnalyzer() *VisibleTextAnalyzer {
+	return &VisibleTextAnalyzer{}
+}
+
+func (a *VisibleTextAnalyzer) Name() string { return AnalyzerVisibleText }
+
+func (a *VisibleTextAnalyzer) Analyze(ctx context.Context, page *Page, result *domain.CrawlResult) error {
+	result.VisibleText = extractVisibleText(page.Document)
+	return nil
+}
+
+// DefaultAnalyzers returns the built-in analyzers in their default run order
+func DefaultAnalyzers(linkChecker *LinkChecker) []Analyzer {
+	return []Analyzer{
+		NewHTMLVersionAnalyzer(),
+		NewTitleAnalyzer(),
+		NewHeadingsAnalyzer(),
+		NewLoginFormAnalyzer(),
+		NewSEOMetadataAnalyzer(),
+		NewStructuredDataAnalyzer(),
+		NewLinksAnalyzer(linkChecker),
+		NewImagesAnalyzer(linkChecker),
+		NewAccessibilityAnalyzer(),
+		NewSecurityAnalyzer(),
+		NewMixedContentAnalyzer(),
+		NewVisibleTextAnalyzer(),
+	}
+}
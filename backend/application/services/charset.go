@@ -0,0 +1,27 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+
+	"golang.org/x/net/html/charset"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// decodeBody detects the character encoding of an HTML body and transcodes it to UTF-8.
+// Following the HTML spec the encoding is taken from a byte order mark, then the Content-Type header,
+// then <meta charset> or http-equiv tags in the first 1024 bytes; pages declaring none are sniffed.
+// It returns the UTF-8 body and the canonical name of the detected encoding.
+func decodeBody(body []byte, contentType string) ([]byte, string, error) {
+	encoding, name, _ := charset.DetermineEncoding(body, contentType)
+	if name == "utf-8" {
+		return bytes.TrimPrefix(body, utf8BOM), name, nil
+	}
+
+	decoded, err := encoding.NewDecoder().Bytes(body)
+	if err != nil {
+		return nil, name, fmt.Errorf("failed to decode %s body: %w", name, err)
+	}
+	return decoded, name, nil
+}
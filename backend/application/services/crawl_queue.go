@@ -0,0 +1,88 @@
+package services
+
+import (
+	"sync"
+
+	"backend/application/commands"
+	"backend/domain"
+)
+
+// CrawlJob is a unit of work processed by the crawl worker pool
+type CrawlJob struct {
+	ID        int
+	Cmd       commands.CrawlCommand
+	SessionID int // Crawl session of a site crawl page, 0 for single page crawls
+	Depth     int
+}
+
+// CrawlQueue is a bounded in-process job queue served by a fixed pool of workers
+type CrawlQueue struct {
+	jobs    chan CrawlJob
+	workers int
+	handler func(CrawlJob)
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewCrawlQueue creates a new CrawlQueue with the given number of workers and buffer size
+func NewCrawlQueue(workers, size int) *CrawlQueue {
+	if workers < 1 {
+		workers = 1
+	}
+	if size < 1 {
+		size = 1
+	}
+	return &CrawlQueue{
+		jobs:    make(chan CrawlJob, size),
+		workers: workers,
+	}
+}
+
+// Start launches the worker goroutines, each calling handler for every job it receives
+func (q *CrawlQueue) Start(handler func(CrawlJob)) {
+	q.handler = handler
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+}
+
+func (q *CrawlQueue) work() {
+	defer q.wg.Done()
+	for job := range q.jobs {
+		q.handler(job)
+	}
+}
+
+// Enqueue adds a job to the queue without blocking
+func (q *CrawlQueue) Enqueue(job CrawlJob) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return domain.ErrCrawlQueueClosed
+	}
+
+	select {
+	case q.jobs <- job:
+		return nil
+	default:
+		return domain.ErrCrawlQueueFull
+	}
+}
+
+// Stop closes the queue and waits for the workers to drain the remaining jobs
+func (q *CrawlQueue) Stop() {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.closed = true
+	close(q.jobs)
+	q.mu.Unlock()
+
+	q.wg.Wait()
+}
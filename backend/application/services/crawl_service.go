@@ -2,12 +2,16 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	"backend/application/commands"
 	"backend/application/queries"
@@ -20,124 +24,645 @@ import (
 // CrawlService handles URL crawling business logic
 type CrawlService struct{
 	crawlResultRepo persistence.CrawlResultRepository
+	brokenLinkRepo  persistence.BrokenLinkRepository
+	structuredRepo  persistence.StructuredDataRepository
+	linkRepo        persistence.LinkRepository
+	imageRepo       persistence.ImageRepository
+	a11yIssueRepo   persistence.AccessibilityIssueRepository
+	headingRepo     persistence.HeadingRepository
+	securityRepo    persistence.SecurityReportRepository
+	mixedRepo       persistence.MixedContentRepository
+	searchBackend   persistence.SearchBackend
+	sessionRepo     persistence.CrawlSessionRepository
+	queue           *CrawlQueue
+	analyzers       *AnalyzerRegistry
+	robots          *RobotsChecker
+	fetcher         Fetcher
+
+	mu       sync.Mutex
+	running  map[int]context.CancelFunc // Cancel functions of the jobs currently being crawled
+	sessions map[int]*siteCrawl         // Site crawl sessions that still have queued or running pages
 }
 
-func NewCrawlService(repo persistence.CrawlResultRepository) *CrawlService {
+func NewCrawlService(repo persistence.CrawlResultRepository, brokenLinkRepo persistence.BrokenLinkRepository, structuredRepo persistence.StructuredDataRepository, linkRepo persistence.LinkRepository, imageRepo persistence.ImageRepository, a11yIssueRepo persistence.AccessibilityIssueRepository, headingRepo persistence.HeadingRepository, securityRepo persistence.SecurityReportRepository, mixedRepo persistence.MixedContentRepository, searchBackend persistence.SearchBackend, sessionRepo persistence.CrawlSessionRepository, queue *CrawlQueue, analyzers *AnalyzerRegistry, robots *RobotsChecker, fetcher Fetcher) *CrawlService {
 	return &CrawlService{
 		crawlResultRepo: repo,
+		brokenLinkRepo:  brokenLinkRepo,
+		structuredRepo:  structuredRepo,
+		linkRepo:        linkRepo,
+		imageRepo:       imageRepo,
+		a11yIssueRepo:   a11yIssueRepo,
+		headingRepo:     headingRepo,
+		securityRepo:    securityRepo,
+		mixedRepo:       mixedRepo,
+		searchBackend:   searchBackend,
+		sessionRepo:     sessionRepo,
+		queue:           queue,
+		analyzers:       analyzers,
+		robots:          robots,
+		fetcher:         fetcher,
+		running:         make(map[int]context.CancelFunc),
+		sessions:        make(map[int]*siteCrawl),
+	}
+}
+
+// Start launches the worker pool that processes queued crawl jobs and recovers the jobs of a previous run
+func (s *CrawlService) Start() {
+	s.queue.Start(s.processJob)
+	s.recoverJobs()
+}
+
+// recoverJobs picks up the jobs the in-memory queue lost when the server stopped: queued jobs are
+// queued again, and jobs that were running are marked as failed since their crawl was aborted.
+// Site crawl sessions cannot follow links without their in-memory state, so running ones are closed
+// as failed; their queued pages are still crawled on their own.
+func (s *CrawlService) recoverJobs() {
+	if _, err := s.sessionRepo.ReplaceStatus(domain.CrawlStatusRunning, domain.CrawlStatusError); err != nil {
+		fmt.Printf("Error closing interrupted crawl sessions: %v\n", err)
+	}
+
+	results, err := s.crawlResultRepo.GetByStatus(domain.CrawlStatusQueued, domain.CrawlStatusRunning)
+	if err != nil {
+		fmt.Printf("Error loading unfinished crawl jobs: %v\n", err)
+		return
+	}
+
+	for _, result := range results {
+		if result.Status == domain.CrawlStatusQueued {
+			crawlCmd := commands.CrawlCommand{URL: result.URL.String, Options: result.Options}
+			err = s.queue.Enqueue(CrawlJob{ID: result.ID, Cmd: crawlCmd, SessionID: result.SessionID, Depth: result.Depth})
+			if err == nil {
+				continue
+			}
+		} else {
+			err = domain.ErrCrawlInterrupted
+		}
+
+		failed, _ := failedCrawl(result, err, "%s", err.Error())
+		if updateErr := s.crawlResultRepo.Update(failed); updateErr != nil {
+			fmt.Printf("Error updating interrupted crawl result %d: %v\n", result.ID, updateErr)
+		}
+	}
+}
+
+// Enqueue stores a queued crawl result for the URL and schedules it for processing.
+// In site crawl mode the result is the root page of a new crawl session.
+func (s *CrawlService) Enqueue(cmd commands.CrawlCommand) (domain.CrawlResult, error) {
+	parsedURL, err := url.Parse(cmd.URL)
+	if err != nil || parsedURL.Host == "" {
+		return domain.CrawlResult{}, domain.ErrInvalidURLFormat
+	}
+
+	analyzers, err := s.analyzers.Select(cmd.Options.Analyzers, cmd.Options.DisabledAnalyzers)
+	if err != nil {
+		return domain.CrawlResult{}, err
+	}
+	// Site crawls follow the internal links that only the links analyzer collects
+	if cmd.Options.SiteCrawl && !containsAnalyzer(analyzers, AnalyzerLinks) {
+		return domain.CrawlResult{}, fmt.Errorf("%w: site crawls require the %s analyzer", domain.ErrInvalidCrawlOptions, AnalyzerLinks)
+	}
+	if err := validateFetchOptions(cmd.Options.Fetch); err != nil {
+		return domain.CrawlResult{}, err
+	}
+
+	result := domain.CrawlResult{
+		URL:           domain.NullString{NullString: sql.NullString{String: cmd.URL, Valid: true}},
+		Status:        domain.CrawlStatusQueued,
+		Options:       cmd.Options,
+		HeadingCounts: make(map[string]int),
+	}
+
+	if cmd.Options.SiteCrawl {
+		if _, err := s.startSiteCrawl(parsedURL, &result); err != nil {
+			return domain.CrawlResult{}, err
+		}
+		cmd.Options = result.Options
+	}
+
+	id, err := s.crawlResultRepo.Save(result)
+	if err != nil {
+		s.releaseSitePage(result.SessionID, 0, domain.CrawlStatusError)
+		return domain.CrawlResult{}, fmt.Errorf("failed to save queued crawl result: %w", err)
+	}
+	result.ID = id
+
+	if err := s.queue.Enqueue(CrawlJob{ID: id, Cmd: cmd, SessionID: result.SessionID}); err != nil {
+		failed, _ := failedCrawl(result, err, "%s", err.Error())
+		if updateErr := s.crawlResultRepo.Update(failed); updateErr != nil {
+			fmt.Printf("Error updating rejected crawl result %d: %v\n", id, updateErr)
+		}
+		s.releaseSitePage(result.SessionID, 0, domain.CrawlStatusError)
+		return domain.CrawlResult{}, err
+	}
+
+	return result, nil
+}
+
+// processJob runs a queued crawl job and records its outcome
+func (s *CrawlService) processJob(job CrawlJob) {
+	// Register the cancel function before the job is marked as running, so a cancel request
+	// that no longer finds the job queued always finds it here
+	ctx, cancel := context.WithCancel(context.Background())
+	s.mu.Lock()
+	s.running[job.ID] = cancel
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, job.ID)
+		s.mu.Unlock()
+		cancel()
+	}()
+
+	// Skip jobs that were cancelled while waiting in the queue
+	started, err := s.crawlResultRepo.CompareAndSetStatus(job.ID, []domain.CrawlStatus{domain.CrawlStatusQueued}, domain.CrawlStatusRunning)
+	if err != nil {
+		fmt.Printf("Error marking crawl result %d as running: %v\n", job.ID, err)
+		s.releaseSitePage(job.SessionID, job.Depth, domain.CrawlStatusError)
+		return
+	}
+	if !started {
+		s.releaseSitePage(job.SessionID, job.Depth, domain.CrawlStatusCancelled)
+		return
+	}
+
+	result, err := s.Crawl(ctx, job.Cmd)
+	result.ID = job.ID
+	switch {
+	case errors.Is(err, domain.ErrCrawlCancelled):
+		result.Status = domain.CrawlStatusCancelled
+	case err != nil:
+		result.Status = domain.CrawlStatusError
+	default:
+		result.Status = domain.CrawlStatusDone
+	}
+
+	// The child rows and the search document are written while the job is still running, so the
+	// job cannot be re-queued and crawled again before they are complete
+	if err := s.brokenLinkRepo.ReplaceForResult(job.ID, result.BrokenLinks); err != nil {
+		fmt.Printf("Error saving broken links of crawl result %d: %v\n", job.ID, err)
+	}
+	if err := s.structuredRepo.ReplaceForResult(job.ID, result.StructuredData, result.StructuredDataIssues); err != nil {
+		fmt.Printf("Error saving structured data of crawl result %d: %v\n", job.ID, err)
+	}
+	if err := s.linkRepo.ReplaceForResult(job.ID, result.Links); err != nil {
+		fmt.Printf("Error saving links of crawl result %d: %v\n", job.ID, err)
+	}
+	if err := s.imageRepo.ReplaceForResult(job.ID, result.Images); err != nil {
+		fmt.Printf("Error saving images of crawl result %d: %v\n", job.ID, err)
+	}
+	if err := s.a11yIssueRepo.ReplaceForResult(job.ID, result.AccessibilityIssues); err != nil {
+		fmt.Printf("Error saving accessibility issues of crawl result %d: %v\n", job.ID, err)
 	}
+	if err := s.headingRepo.ReplaceForResult(job.ID, result.Headings); err != nil {
+		fmt.Printf("Error saving headings of crawl result %d: %v\n", job.ID, err)
+	}
+	if err := s.securityRepo.ReplaceForResult(job.ID, result.SecurityReport); err != nil {
+		fmt.Printf("Error saving security report of crawl result %d: %v\n", job.ID, err)
+	}
+	if err := s.mixedRepo.ReplaceForResult(job.ID, result.MixedContent); err != nil {
+		fmt.Printf("Error saving mixed content of crawl result %d: %v\n", job.ID, err)
+	}
+	if err := s.indexSearchDocument(result); err != nil {
+		fmt.Printf("Error indexing crawl result %d for search: %v\n", job.ID, err)
+	}
+
+	// The final status is written last; RequeueCrawls only picks up finished jobs
+	result = s.finishJob(result)
+
+	if result.Status == domain.CrawlStatusDone {
+		s.followLinks(job, result)
+	}
+	s.releaseSitePage(job.SessionID, job.Depth, result.Status)
+}
+
+// finishJob saves the crawl result with its final status. A result that cannot be saved is recorded
+// as failed instead, so the job never stays running.
+func (s *CrawlService) finishJob(result domain.CrawlResult) domain.CrawlResult {
+	err := s.crawlResultRepo.Update(result)
+	if err == nil {
+		return result
+	}
+	fmt.Printf("Error saving crawl result %d: %v\n", result.ID, err)
+
+	failed, _ := failedCrawl(result, err, "Failed to save crawl result: %v", err)
+	if err := s.crawlResultRepo.Update(failed); err != nil {
+		fmt.Printf("Error saving failed crawl result %d: %v\n", result.ID, err)
+		if _, err := s.crawlResultRepo.CompareAndSetStatus(result.ID, []domain.CrawlStatus{domain.CrawlStatusRunning}, domain.CrawlStatusError); err != nil {
+			fmt.Printf("Error marking crawl result %d as failed: %v\n", result.ID, err)
+		}
+	}
+	if err := s.searchBackend.Remove(result.ID); err != nil {
+		fmt.Printf("Error removing crawl result %d from search: %v\n", result.ID, err)
+	}
+	return failed
+}
+
+// indexSearchDocument indexes the text of a finished crawl; failed crawls are removed from the search index
+func (s *CrawlService) indexSearchDocument(result domain.CrawlResult) error {
+	if result.Status != domain.CrawlStatusDone {
+		return s.searchBackend.Remove(result.ID)
+	}
+
+	headings := make([]string, len(result.Headings))
+	for i, heading := range result.Headings {
+		headings[i] = heading.Text
+	}
+	return s.searchBackend.Index(domain.SearchDocument{
+		CrawlResultID:   result.ID,
+		Title:           result.PageTitle,
+		MetaDescription: result.MetaDescription,
+		Headings:        strings.Join(headings, "\n"),
+		Body:            result.VisibleText,
+	})
+}
+
+// CancelCrawls stops queued or running crawl jobs and returns the IDs that were cancelled
+func (s *CrawlService) CancelCrawls(cmd commands.CancelCrawlsCommand) ([]int, error) {
+	cancelled := []int{}
+	for _, id := range cmd.IDs {
+		// Queued jobs are marked as cancelled and skipped once a worker picks them up
+		ok, err := s.crawlResultRepo.CompareAndSetStatus(id, []domain.CrawlStatus{domain.CrawlStatusQueued}, domain.CrawlStatusCancelled)
+		if err != nil {
+			return cancelled, fmt.Errorf("failed to cancel crawl job %d: %w", id, err)
+		}
+		if ok {
+			cancelled = append(cancelled, id)
+			continue
+		}
+
+		// Running jobs abort their in-flight requests and record the cancellation themselves
+		s.mu.Lock()
+		cancel, isRunning := s.running[id]
+		s.mu.Unlock()
+		if isRunning {
+			cancel()
+			cancelled = append(cancelled, id)
+		}
+	}
+
+	if len(cmd.IDs) > 0 && len(cancelled) == 0 {
+		return cancelled, domain.ErrNoCancellableCrawls
+	}
+	return cancelled, nil
 }
 
-// saveFailedCrawl handles the logic for saving a crawl result when an error occurs.
-func (s *CrawlService) saveFailedCrawl(result domain.CrawlResult, crawlError error, format string, args ...any) (domain.CrawlResult, int, error) {
+// RequeueCrawls puts finished crawl jobs back on the queue and returns the IDs that were re-queued
+func (s *CrawlService) RequeueCrawls(cmd commands.RequeueCrawlsCommand) ([]int, error) {
+	requeued := []int{}
+	for _, id := range cmd.IDs {
+		result, err := s.crawlResultRepo.GetByID(id)
+		if errors.Is(err, domain.ErrCrawlResultNotFound) {
+			continue
+		}
+		if err != nil {
+			return requeued, fmt.Errorf("failed to get crawl job %d: %w", id, err)
+		}
+		if !result.Status.IsFinished() {
+			continue
+		}
+
+		ok, err := s.crawlResultRepo.CompareAndSetStatus(id, []domain.CrawlStatus{result.Status}, domain.CrawlStatusQueued)
+		if err != nil {
+			return requeued, fmt.Errorf("failed to re-queue crawl job %d: %w", id, err)
+		}
+		if !ok {
+			continue
+		}
+
+		crawlCmd := commands.CrawlCommand{URL: result.URL.String, Options: result.Options}
+		job := CrawlJob{ID: id, Cmd: crawlCmd, SessionID: s.reopenSitePage(result.SessionID), Depth: result.Depth}
+		if err := s.queue.Enqueue(job); err != nil {
+			failed, _ := failedCrawl(result, err, "%s", err.Error())
+			if updateErr := s.crawlResultRepo.Update(failed); updateErr != nil {
+				fmt.Printf("Error updating rejected crawl result %d: %v\n", id, updateErr)
+			}
+			s.releaseSitePage(job.SessionID, job.Depth, domain.CrawlStatusError)
+			return requeued, err
+		}
+		requeued = append(requeued, id)
+	}
+
+	if len(cmd.IDs) > 0 && len(requeued) == 0 {
+		return requeued, domain.ErrNoRequeueableCrawls
+	}
+	return requeued, nil
+}
+
+// failedCrawl fills in the fields of a crawl result when an error occurs.
+func failedCrawl(result domain.CrawlResult, crawlError error, format string, args ...any) (domain.CrawlResult, error) {
+	result.Status = domain.CrawlStatusError
 	result.Error = fmt.Sprintf(format, args...)
 	result.InaccessibleLinkCount = 0
 	result.InternalLinkCount = 0
 	result.ExternalLinkCount = 0
+	result.BrokenLinks = nil
+	result.StructuredData = nil
+	result.StructuredDataIssues = nil
+	result.InternalLinks = nil
+	result.Links = nil
+	result.Images = nil
+	result.ImageCount = 0
+	result.ImagesMissingAltCount = 0
+	result.ImagesEmptyAltCount = 0
+	result.ImagesMissingDimensionsCount = 0
+	result.BrokenImageCount = 0
+	result.AccessibilityIssues = nil
+	result.AccessibilityErrorCount = 0
+	result.AccessibilityWarningCount = 0
+	result.Headings = nil
+	result.MultipleH1 = false
+	result.MissingH1 = false
+	result.HeadingLevelSkips = 0
+	result.MixedContent = nil
+	result.ActiveMixedContentCount = 0
+	result.PassiveMixedContentCount = 0
+	result.SecurityReport = nil
+	result.SecurityScore = nil
+	result.SecurityGrade = ""
+	result.HasLoginForm = false
+	result.LoginFormConfidence = 0
+	result.LoginFormAction = ""
 	result.PageTitle = "CRAWLING HAS FAILED"
-
-	id, saveErr := s.crawlResultRepo.Save(result)
-	if saveErr != nil {
-		fmt.Printf("Error saving failed crawl result: %v (original error: %v)\n", saveErr, crawlError)
-	}
-	return result, id, crawlError
+	return result, crawlError
 }
 
-func (s *CrawlService) Crawl(cmd commands.CrawlCommand) (domain.CrawlResult, int, error) {
+// Crawl fetches the URL and extracts the page information into a CrawlResult
+// The outbound request is aborted when ctx is cancelled.
+func (s *CrawlService) Crawl(ctx context.Context, cmd commands.CrawlCommand) (domain.CrawlResult, error) {
 	result := domain.CrawlResult{
 		URL:           domain.NullString{NullString: sql.NullString{String: cmd.URL, Valid: true}},
 		HeadingCounts: make(map[string]int),
+		RedirectChain: []domain.RedirectHop{},
 	}
 
 	parsedURL, err := url.Parse(cmd.URL)
 	if err != nil {
-		// No need to save, as the URL is invalid from the start.
-		result.Error = domain.ErrInvalidURLFormat.Error()
-		return result, 0, domain.ErrInvalidURLFormat
+		return failedCrawl(result, domain.ErrInvalidURLFormat, "%s", domain.ErrInvalidURLFormat.Error())
 	}
 
-	res, err := http.Get(cmd.URL)
+	analyzers, err := s.analyzers.Select(cmd.Options.Analyzers, cmd.Options.DisabledAnalyzers)
 	if err != nil {
-		return s.saveFailedCrawl(result, domain.ErrURLFetchFailed, "%s: %v", domain.ErrURLFetchFailed.Error(), err)
+		return failedCrawl(result, err, "%s", err.Error())
+	}
+
+	// Honor robots.txt and Crawl-delay unless the site owner opted out
+	if !cmd.Options.IgnoreRobots {
+		allowed, delay := s.robots.Allowed(ctx, parsedURL, cmd.Options.Fetch)
+		if ctx.Err() != nil {
+			return failedCrawl(result, domain.ErrCrawlCancelled, "%s", domain.ErrCrawlCancelled.Error())
+		}
+		if !allowed {
+			return failedCrawl(result, domain.ErrDisallowedByRobots, "%s (user agent %q)", domain.ErrDisallowedByRobots.Error(), s.robots.UserAgent())
+		}
+		if err := s.robots.Wait(ctx, parsedURL, delay); err != nil {
+			return failedCrawl(result, domain.ErrCrawlCancelled, "%s", domain.ErrCrawlCancelled.Error())
+		}
+	}
+
+	fetchStarted := time.Now()
+	res, err := s.fetcher.Fetch(ctx, http.MethodGet, cmd.URL, cmd.Options.Fetch)
+	if res != nil {
+		result.RedirectChain = res.Redirects
+	}
+	switch {
+	case errors.Is(err, domain.ErrRedirectLoop):
+		return failedCrawl(result, domain.ErrRedirectLoop, "%v", err)
+	case errors.Is(err, domain.ErrTooManyRedirects):
+		return failedCrawl(result, domain.ErrTooManyRedirects, "%v", err)
+	case err != nil && ctx.Err() != nil:
+		return failedCrawl(result, domain.ErrCrawlCancelled, "%s", domain.ErrCrawlCancelled.Error())
+	case err != nil:
+		return failedCrawl(result, domain.ErrURLFetchFailed, "%s: %v", domain.ErrURLFetchFailed.Error(), err)
 	}
 	defer res.Body.Close()
 
+	// Links are resolved and classified against the page we ended up on
+	finalURL := res.Request.URL
+	result.FinalURL = finalURL.String()
+
+	result.HTTPStatusCode = res.StatusCode
+	result.HTTPProtocol = res.Proto
+	result.ContentType = res.Header.Get("Content-Type")
+	result.ContentLength = res.ContentLength
+	result.DNSLookupMs = res.Timing.DNSLookup.Milliseconds()
+	result.ConnectMs = res.Timing.Connect.Milliseconds()
+	result.TLSHandshakeMs = res.Timing.TLSHandshake.Milliseconds()
+	result.TTFBMs = res.Timing.FirstByte.Milliseconds()
+
 	if res.StatusCode >= 400 {
-		return s.saveFailedCrawl(result, domain.ErrURLFetchFailed, "URL returned status code: %d", res.StatusCode)
+		return failedCrawl(result, domain.ErrURLFetchFailed, "URL returned status code: %d", res.StatusCode)
 	}
 
-	bodyBytes, err := ioutil.ReadAll(res.Body)
+	bodyBytes, err := io.ReadAll(res.Body)
+	result.TransferSize = res.TransferSize()
+	result.BodySize = int64(len(bodyBytes))
+	result.DownloadMs = time.Since(res.Timing.Start.Add(res.Timing.FirstByte)).Milliseconds()
+	result.TotalTimeMs = time.Since(fetchStarted).Milliseconds()
+	if errors.Is(err, domain.ErrResponseTooLarge) {
+		return failedCrawl(result, domain.ErrResponseTooLarge, "%s", domain.ErrResponseTooLarge.Error())
+	}
+	if err != nil && ctx.Err() != nil {
+		return failedCrawl(result, domain.ErrCrawlCancelled, "%s", domain.ErrCrawlCancelled.Error())
+	}
 	if err != nil {
 		wrappedErr := fmt.Errorf("failed to read response body: %w", err)
-		return s.saveFailedCrawl(result, wrappedErr, "Failed to read response body: %v", err)
+		return failedCrawl(result, wrappedErr, "Failed to read response body: %v", err)
+	}
+
+	// Transcode non-UTF-8 pages before parsing so extracted text is not garbled
+	bodyBytes, result.Charset, err = decodeBody(bodyBytes, res.Header.Get("Content-Type"))
+	if err != nil {
+		return failedCrawl(result, domain.ErrHTMLParseFailed, "%s: %v", domain.ErrHTMLParseFailed.Error(), err)
 	}
 
 	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(bodyBytes))
 	if err != nil {
-		return s.saveFailedCrawl(result, domain.ErrHTMLParseFailed, "%s: %v", domain.ErrHTMLParseFailed.Error(), err)
+		return failedCrawl(result, domain.ErrHTMLParseFailed, "%s: %v", domain.ErrHTMLParseFailed.Error(), err)
 	}
 
-	// HTML Version
-	bodyString := string(bodyBytes)
-	if strings.Contains(strings.ToLower(bodyString), "<!doctype html>") {
-		result.HTMLVersion = "HTML5"
-	} else if strings.Contains(bodyString, `<!DOCTYPE HTML PUBLIC "-//W3C//DTD HTML 4.01//EN" "http://www.w3.org/TR/html4/strict.dtd">`) {
-		result.HTMLVersion = "HTML 4.01 Strict"
-	} else if strings.Contains(bodyString, `<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Strict//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-strict.dtd">`) {
-		result.HTMLVersion = "XHTML 1.0 Strict"
-	} else {
-		result.HTMLVersion = "Unknown/Other"
+	page := &Page{
+		URL:      finalURL,
+		Response: res.Response,
+		Body:     bodyBytes,
+		Document: doc,
+		Options:  cmd.Options,
+	}
+	for _, analyzer := range analyzers {
+		if err := analyzer.Analyze(ctx, page, &result); err != nil {
+			if ctx.Err() != nil {
+				return failedCrawl(result, domain.ErrCrawlCancelled, "%s", domain.ErrCrawlCancelled.Error())
+			}
+			return failedCrawl(result, domain.ErrAnalyzerFailed, "%s (%s): %v", domain.ErrAnalyzerFailed.Error(), analyzer.Name(), err)
+		}
 	}
 
-	// Page Title
-	result.PageTitle = doc.Find("title").Text()
-	if result.PageTitle == "" {
-		result.PageTitle = "NO TITLE"
+	return result, nil
+}
+
+// GetCrawlResult retrieves a single crawl result, including its current status
+func (s *CrawlService) GetCrawlResult(id int) (domain.CrawlResult, error) {
+	result, err := s.crawlResultRepo.GetByID(id)
+	if err != nil {
+		return domain.CrawlResult{}, fmt.Errorf("failed to get crawl result from repository: %w", err)
 	}
+	return result, nil
+}
 
-	// Count heading tags
-	for i := 1; i <= 6; i++ {
-		heading := fmt.Sprintf("h%d", i)
-		count := doc.Find(heading).Length()
-		if count > 0 {
-			result.HeadingCounts[heading] = count
-		}
+// GetBrokenLinks retrieves the broken links found on a crawled page
+func (s *CrawlService) GetBrokenLinks(id int) ([]domain.BrokenLink, error) {
+	if _, err := s.crawlResultRepo.GetByID(id); err != nil {
+		return nil, fmt.Errorf("failed to get crawl result from repository: %w", err)
 	}
 
-	// Count links
-	doc.Find("a").Each(func(i int, s *goquery.Selection) {
-		link, exists := s.Attr("href")
-		if !exists {
-			return
-		}
-		resolvedLink, err := parsedURL.Parse(link)
-		if err != nil {
-			result.InaccessibleLinkCount++
-			return
-		}
-		if resolvedLink.Host == parsedURL.Host {
-			result.InternalLinkCount++
-		} else {
-			result.ExternalLinkCount++
-		}
-	})
+	links, err := s.brokenLinkRepo.GetByResultID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get broken links from repository: %w", err)
+	}
+	return links, nil
+}
+
+// GetLinksResponse holds a page of the link inventory of a crawl result
+type GetLinksResponse struct {
+	List       []domain.Link `json:"list"`
+	TotalCount int           `json:"total_count"`
+}
+
+// GetLinks retrieves a paginated, filtered list of the links found on a crawled page
+func (s *CrawlService) GetLinks(query queries.GetCrawlLinksQuery) (GetLinksResponse, error) {
+	if query.CurrPage < 1 {
+		query.CurrPage = 1
+	}
+	if query.PageSize < 1 {
+		query.PageSize = 10
+	}
+
+	if _, err := s.crawlResultRepo.GetByID(query.CrawlResultID); err != nil {
+		return GetLinksResponse{}, fmt.Errorf("failed to get crawl result from repository: %w", err)
+	}
+
+	links, totalCount, err := s.linkRepo.GetByResultID(query.CrawlResultID, query.CurrPage, query.PageSize, query.Filter)
+	if err != nil {
+		return GetLinksResponse{}, fmt.Errorf("failed to get links from repository: %w", err)
+	}
+
+	return GetLinksResponse{
+		List:       links,
+		TotalCount: totalCount,
+	}, nil
+}
+
+// GetImages retrieves the image inventory of a crawled page
+func (s *CrawlService) GetImages(id int) ([]domain.Image, error) {
+	if _, err := s.crawlResultRepo.GetByID(id); err != nil {
+		return nil, fmt.Errorf("failed to get crawl result from repository: %w", err)
+	}
+
+	images, err := s.imageRepo.GetByResultID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get images from repository: %w", err)
+	}
+	return images, nil
+}
+
+// GetAccessibilityIssues retrieves the accessibility issues of a crawled page, optionally only those of one severity
+func (s *CrawlService) GetAccessibilityIssues(id int, severity string) ([]domain.AccessibilityIssue, error) {
+	if _, err := s.crawlResultRepo.GetByID(id); err != nil {
+		return nil, fmt.Errorf("failed to get crawl result from repository: %w", err)
+	}
+
+	issues, err := s.a11yIssueRepo.GetByResultID(id, severity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accessibility issues from repository: %w", err)
+	}
+	return issues, nil
+}
+
+// GetMixedContent retrieves the insecure subresources and form targets of a crawled page, optionally only active or passive ones
+func (s *CrawlService) GetMixedContent(id int, contentType string) ([]domain.MixedContent, error) {
+	if _, err := s.crawlResultRepo.GetByID(id); err != nil {
+		return nil, fmt.Errorf("failed to get crawl result from repository: %w", err)
+	}
+
+	items, err := s.mixedRepo.GetByResultID(id, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mixed content from repository: %w", err)
+	}
+	return items, nil
+}
+
+// GetHeadingOutline retrieves the crawl result together with its heading outline in document order
+func (s *CrawlService) GetHeadingOutline(id int) (domain.CrawlResult, []domain.Heading, error) {
+	result, err := s.crawlResultRepo.GetByID(id)
+	if err != nil {
+		return domain.CrawlResult{}, nil, fmt.Errorf("failed to get crawl result from repository: %w", err)
+	}
+
+	headings, err := s.headingRepo.GetByResultID(id)
+	if err != nil {
+		return domain.CrawlResult{}, nil, fmt.Errorf("failed to get headings from repository: %w", err)
+	}
+	return result, headings, nil
+}
+
+// GetSecurityReportResponse holds the security report of a crawl result compared with the previous report for its URL
+type GetSecurityReportResponse struct {
+	Report      domain.SecurityReport  `json:"report"`
+	Previous    *domain.SecurityReport `json:"previous"`    // nil for the first audited crawl of the URL
+	Regressions []string               `json:"regressions"` // Checks whose status got worse since the previous report
+}
+
+// GetSecurityReport retrieves the security report of a crawled page and the checks that regressed since the last crawl of its URL
+func (s *CrawlService) GetSecurityReport(id int) (GetSecurityReportResponse, error) {
+	if _, err := s.crawlResultRepo.GetByID(id); err != nil {
+		return GetSecurityReportResponse{}, fmt.Errorf("failed to get crawl result from repository: %w", err)
+	}
+
+	report, err := s.securityRepo.GetByResultID(id)
+	if err != nil {
+		return GetSecurityReportResponse{}, fmt.Errorf("failed to get security report from repository: %w", err)
+	}
+
+	previous, err := s.securityRepo.GetPreviousForURL(id)
+	if err != nil {
+		return GetSecurityReportResponse{}, fmt.Errorf("failed to get previous security report from repository: %w", err)
+	}
+
+	return GetSecurityReportResponse{
+		Report:      report,
+		Previous:    previous,
+		Regressions: securityRegressions(previous, report),
+	}, nil
+}
 
-	// Save the successful result
-	id, saveErr := s.crawlResultRepo.Save(result)
-	if saveErr != nil {
-		result.Error = fmt.Sprintf("failed to save crawl result: %v", saveErr)
-		return result, 0, saveErr
+// GetStructuredData retrieves the structured data entities and parse issues of a crawled page
+func (s *CrawlService) GetStructuredData(id int) ([]domain.StructuredDataEntity, []domain.StructuredDataIssue, error) {
+	if _, err := s.crawlResultRepo.GetByID(id); err != nil {
+		return nil, nil, fmt.Errorf("failed to get crawl result from repository: %w", err)
 	}
 
-	return result, id, nil
+	entities, issues, err := s.structuredRepo.GetByResultID(id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get structured data from repository: %w", err)
+	}
+	return entities, issues, nil
+}
+
+// GetAnalyzers returns the names of the analyzers that can be enabled or disabled per crawl
+func (s *CrawlService) GetAnalyzers() []string {
+	return s.analyzers.Names()
 }
 
 type GetCrawlResultsResponse struct {
-	List       []domain.CrawlResult `json:"list"`
-	TotalCount int                  `json:"total_count"`
+	List            []domain.CrawlResult `json:"list"`
+	TotalCount      *int                 `json:"total_count"` // nil when counting was skipped
+	TotalCountExact bool                 `json:"total_count_exact"`
+	NextCursor      string               `json:"next_cursor,omitempty"` // Keyset paging only
+	PrevCursor      string               `json:"prev_cursor,omitempty"` // Keyset paging only
 }
 
-// GetCrawlResults retrieves a paginated list of crawl results
+// GetCrawlResults retrieves a page of crawl results, by offset or by keyset cursor, with an optional total count.
+// A full-text search restricts the list to its matches, by default the most relevant first, and adds their snippets.
 func (s *CrawlService) GetCrawlResults(query queries.GetCrawlResultsQuery) (GetCrawlResultsResponse, error) {
 	if query.CurrPage < 1 {
 		query.CurrPage = 1
@@ -145,16 +670,66 @@ func (s *CrawlService) GetCrawlResults(query queries.GetCrawlResultsQuery) (GetC
 	if query.PageSize < 1 {
 		query.PageSize = 10
 	}
+	if query.Count == "" {
+		query.Count = domain.CountExact
+		if query.UseCursor {
+			query.Count = domain.CountNone
+		}
+	}
+
+	query.Filter.Search = query.Search
 
-	results, totalCount, err := s.crawlResultRepo.GetAll(query.CurrPage, query.PageSize, query.Query, query.SortingJson)
+	var response GetCrawlResultsResponse
+	var err error
+	if query.UseCursor {
+		response.List, response.NextCursor, response.PrevCursor, err = s.crawlResultRepo.GetPage(query.Cursor, query.PageSize, query.Query, query.Sorting, query.Filter)
+	} else {
+		response.List, err = s.crawlResultRepo.GetAll(query.CurrPage, query.PageSize, query.Query, query.Sorting, query.Filter)
+	}
+	if errors.Is(err, domain.ErrInvalidSorting) || errors.Is(err, domain.ErrInvalidFilter) || errors.Is(err, domain.ErrInvalidCursor) {
+		return GetCrawlResultsResponse{}, err
+	}
 	if err != nil {
 		return GetCrawlResultsResponse{}, fmt.Errorf("failed to get crawl results from repository: %w", err)
 	}
 
-	return GetCrawlResultsResponse{
-		List:       results,
-		TotalCount: totalCount,
-	}, nil
+	var totalCount int
+	switch query.Count {
+	case domain.CountExact:
+		totalCount, err = s.crawlResultRepo.GetTotalCount(query.Query, query.Filter)
+		response.TotalCount, response.TotalCountExact = &totalCount, true
+	case domain.CountEstimated:
+		totalCount, response.TotalCountExact, err = s.crawlResultRepo.EstimateTotalCount(query.Query, query.Filter)
+		response.TotalCount = &totalCount
+	}
+	if err != nil {
+		return GetCrawlResultsResponse{}, fmt.Errorf("failed to get total count of crawl results: %w", err)
+	}
+
+	if query.Search != "" {
+		if err := s.addSearchSnippets(query.Search, response.List); err != nil {
+			return GetCrawlResultsResponse{}, err
+		}
+	}
+
+	return response, nil
+}
+
+// addSearchSnippets sets the highlighted snippet of each search result
+func (s *CrawlService) addSearchSnippets(search string, results []domain.CrawlResult) error {
+	ids := make([]int, len(results))
+	for i, result := range results {
+		ids[i] = result.ID
+	}
+
+	snippets, err := s.searchBackend.Snippets(search, ids)
+	if err != nil {
+		return fmt.Errorf("failed to build search snippets: %w", err)
+	}
+	for i := range results {
+		results[i].Snippet = snippets[results[i].ID]
+	}
+	return nil
 }
 
 // DeleteCrawlResults deletes multiple crawl results by IDs
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// FetchTiming holds the phases of the request that produced the final response of a fetch.
+// Phases are zero when a pooled connection was reused.
+type FetchTiming struct {
+	Start        time.Time
+	DNSLookup    time.Duration
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	FirstByte    time.Duration // From Start to the first response byte
+}
+
+// timingRecorder collects FetchTiming from httptrace callbacks, which may run on other goroutines
+type timingRecorder struct {
+	mu                     sync.Mutex
+	timing                 FetchTiming
+	dnsStart, connectStart time.Time
+	tlsStart               time.Time
+}
+
+func newTimingRecorder() *timingRecorder {
+	return &timingRecorder{timing: FetchTiming{Start: time.Now()}}
+}
+
+// withTrace returns ctx with a ClientTrace reporting to the recorder
+func (r *timingRecorder) withTrace(ctx context.Context) context.Context {
+	record := func(fn func()) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		fn()
+	}
+
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { record(func() { r.dnsStart = time.Now() }) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { record(func() { r.timing.DNSLookup = time.Since(r.dnsStart) }) },
+		ConnectStart: func(network, addr string) {
+			record(func() {
+				if r.connectStart.IsZero() {
+					r.connectStart = time.Now()
+				}
+			})
+		},
+		ConnectDone: func(network, addr string, err error) {
+			record(func() {
+				if err == nil {
+					r.timing.Connect = time.Since(r.connectStart)
+				}
+			})
+		},
+		TLSHandshakeStart: func() { record(func() { r.tlsStart = time.Now() }) },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			record(func() { r.timing.TLSHandshake = time.Since(r.tlsStart) })
+		},
+		GotFirstResponseByte: func() { record(func() { r.timing.FirstByte = time.Since(r.timing.Start) }) },
+	})
+}
+
+func (r *timingRecorder) result() FetchTiming {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.timing
+}
+
+// countingReadCloser counts the bytes read through it
+type countingReadCloser struct {
+	io.ReadCloser
+	mu sync.Mutex
+	n  int64
+}
+
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.mu.Lock()
+	r.n += int64(n)
+	r.mu.Unlock()
+	return n, err
+}
+
+func (r *countingReadCloser) count() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.n
+}
+
+// gzipReadCloser decompresses a gzip body, opening the gzip stream on the first read so empty bodies do not fail
+type gzipReadCloser struct {
+	body io.ReadCloser
+	zr   *gzip.Reader
+}
+
+func (r *gzipReadCloser) Read(p []byte) (int, error) {
+	if r.zr == nil {
+		zr, err := gzip.NewReader(r.body)
+		if err != nil {
+			return 0, err
+		}
+		r.zr = zr
+	}
+	return r.zr.Read(p)
+}
+
+func (r *gzipReadCloser) Close() error {
+	return r.body.Close()
+}
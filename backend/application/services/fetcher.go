@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"backend/domain"
+)
+
+// Fetcher sends outbound HTTP requests for the crawler
+type Fetcher interface {
+	// Fetch requests the URL with opts merged over the fetcher's defaults, following redirects.
+	// Reading more than the allowed number of body bytes fails with domain.ErrResponseTooLarge.
+	// When following redirects fails, the returned FetchResponse still holds the redirects followed so far.
+	Fetch(ctx context.Context, method, rawURL string, opts domain.FetchOptions) (*FetchResponse, error)
+}
+
+// FetchResponse is the final response of a fetch together with the redirects that led to it
+type FetchResponse struct {
+	*http.Response
+	Redirects []domain.RedirectHop
+	Timing    FetchTiming
+
+	wire *countingReadCloser
+}
+
+// TransferSize returns the number of body bytes received so far, before decompression
+func (r *FetchResponse) TransferSize() int64 {
+	if r.wire == nil {
+		return 0
+	}
+	return r.wire.count()
+}
+
+// HTTPFetcher implements Fetcher on top of net/http
+type HTTPFetcher struct {
+	defaults domain.FetchOptions
+
+	mu             sync.Mutex
+	transports     map[string]*http.Transport // Keyed by proxy URL so connections are reused
+	transportOrder []string                   // Keys of transports, least recently used first
+}
+
+// NewHTTPFetcher creates a new HTTPFetcher with the given default options
+func NewHTTPFetcher(defaults domain.FetchOptions) *HTTPFetcher {
+	return &HTTPFetcher{
+		defaults:   defaults,
+		transports: make(map[string]*http.Transport),
+	}
+}
+
+// Fetch implements Fetcher. Redirects are followed one hop at a time so each of them can be recorded.
+func (f *HTTPFetcher) Fetch(ctx context.Context, method, rawURL string, opts domain.FetchOptions) (*FetchResponse, error) {
+	opts = mergeFetchOptions(f.defaults, opts)
+
+	transport, err := f.transport(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(opts.ConnectTimeoutMs+opts.ReadTimeoutMs) * time.Millisecond,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	fetched := &FetchResponse{}
+	visited := make(map[string]bool)
+	for {
+		req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+		if err != nil {
+			return fetched, err
+		}
+		setFetchHeaders(req, opts)
+
+		// Ask for gzip ourselves; net/http would otherwise decompress transparently and hide the transfer size.
+		// A caller's Accept-Encoding is replaced, since bodies in other encodings could not be parsed.
+		req.Header.Set("Accept-Encoding", "gzip")
+
+		recorder := newTimingRecorder()
+		req = req.WithContext(recorder.withTrace(withConnectTimeout(req.Context(), opts.ConnectTimeoutMs)))
+
+		started := time.Now()
+		res, err := client.Do(req)
+		if err != nil {
+			return fetched, err
+		}
+
+		location := res.Header.Get("Location")
+		if !isRedirectStatus(res.StatusCode) || location == "" || opts.MaxRedirects < 0 {
+			fetched.wire = &countingReadCloser{ReadCloser: res.Body}
+			res.Body = fetched.wire
+			if strings.EqualFold(res.Header.Get("Content-Encoding"), "gzip") {
+				res.Body = &gzipReadCloser{body: res.Body}
+				res.Header.Del("Content-Encoding")
+				res.Uncompressed = true
+			}
+			if opts.MaxBodyBytes > 0 {
+				res.Body = &maxBytesReadCloser{ReadCloser: res.Body, remaining: opts.MaxBodyBytes}
+			}
+			fetched.Response = res
+			fetched.Timing = recorder.result()
+			return fetched, nil
+		}
+
+		// Drain a little of the body so the connection can be reused
+		io.CopyN(io.Discard, res.Body, 4096)
+		res.Body.Close()
+
+		fetched.Redirects = append(fetched.Redirects, domain.RedirectHop{
+			URL:        req.URL.String(),
+			StatusCode: res.StatusCode,
+			Location:   location,
+			LatencyMs:  time.Since(started).Milliseconds(),
+		})
+		visited[req.URL.String()] = true
+
+		next, err := req.URL.Parse(location)
+		if err != nil || (next.Scheme != "http" && next.Scheme != "https") {
+			return fetched, fmt.Errorf("invalid redirect location %q", location)
+		}
+		if visited[next.String()] {
+			return fetched, fmt.Errorf("%w: %s redirects back to %s", domain.ErrRedirectLoop, req.URL, next)
+		}
+		if len(fetched.Redirects) > opts.MaxRedirects {
+			return fetched, fmt.Errorf("%w: stopped after %d redirects", domain.ErrTooManyRedirects, opts.MaxRedirects)
+		}
+
+		// Like browsers, follow a 303 See Other with GET
+		if res.StatusCode == http.StatusSeeOther && method != http.MethodHead {
+			method = http.MethodGet
+		}
+		rawURL = next.String()
+	}
+}
+
+func isRedirectStatus(code int) bool {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// maxCachedTransports bounds the transports kept for reuse; each one holds its own idle connections
+const maxCachedTransports = 16
+
+// connectTimeoutKey is the context key of the connect timeout of a request
+type connectTimeoutKey struct{}
+
+func withConnectTimeout(ctx context.Context, timeoutMs int) context.Context {
+	return context.WithValue(ctx, connectTimeoutKey{}, time.Duration(timeoutMs)*time.Millisecond)
+}
+
+// dialContext dials with the connect timeout of the request, so one transport serves every timeout
+func dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	timeout, _ := ctx.Value(connectTimeoutKey{}).(time.Duration)
+	dialer := &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}
+	return dialer.DialContext(ctx, network, address)
+}
+
+// transport returns a shared transport for the proxy of opts. Timeouts are applied per request: the
+// connect timeout by dialContext and the overall deadline by the client. The least recently used
+// transport is closed once more than maxCachedTransports proxies are in use.
+func (f *HTTPFetcher) transport(opts domain.FetchOptions) (*http.Transport, error) {
+	key := opts.ProxyURL
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if transport, ok := f.transports[key]; ok {
+		f.touchTransport(key)
+		return transport, nil
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if opts.ProxyURL != "" {
+		proxyURL, err := url.Parse(opts.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid proxy URL: %v", domain.ErrInvalidCrawlOptions, err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	transport := &http.Transport{
+		Proxy:             proxy,
+		DialContext:       dialContext,
+		ForceAttemptHTTP2: true,
+		MaxIdleConns:      100,
+		IdleConnTimeout:   90 * time.Second,
+	}
+
+	if len(f.transportOrder) >= maxCachedTransports {
+		oldest := f.transportOrder[0]
+		f.transports[oldest].CloseIdleConnections()
+		delete(f.transports, oldest)
+		f.transportOrder = f.transportOrder[1:]
+	}
+	f.transports[key] = transport
+	f.transportOrder = append(f.transportOrder, key)
+	return transport, nil
+}
+
+// touchTransport marks the transport of key as the most recently used
+func (f *HTTPFetcher) touchTransport(key string) {
+	for i, candidate := range f.transportOrder {
+		if candidate == key {
+			f.transportOrder = append(append(f.transportOrder[:i:i], f.transportOrder[i+1:]...), key)
+			return
+		}
+	}
+}
+
+func setFetchHeaders(req *http.Request, opts domain.FetchOptions) {
+	for name, value := range opts.Headers {
+		req.Header.Set(name, value)
+	}
+	if opts.UserAgent != "" {
+		req.Header.Set("User-Agent", opts.UserAgent)
+	}
+}
+
+// mergeFetchOptions returns base with every option that is set in override replacing it
+func mergeFetchOptions(base, override domain.FetchOptions) domain.FetchOptions {
+	merged := base
+	if override.ConnectTimeoutMs > 0 {
+		merged.ConnectTimeoutMs = override.ConnectTimeoutMs
+	}
+	if override.ReadTimeoutMs > 0 {
+		merged.ReadTimeoutMs = override.ReadTimeoutMs
+	}
+	if override.MaxBodyBytes > 0 {
+		merged.MaxBodyBytes = override.MaxBodyBytes
+	}
+	if override.MaxRedirects != 0 {
+		merged.MaxRedirects = override.MaxRedirects
+	}
+	if override.UserAgent != "" {
+		merged.UserAgent = override.UserAgent
+	}
+	if override.ProxyURL != "" {
+		merged.ProxyURL = override.ProxyURL
+	}
+	if len(override.Headers) > 0 {
+		merged.Headers = make(map[string]string, len(base.Headers)+len(override.Headers))
+		for name, value := range base.Headers {
+			merged.Headers[name] = value
+		}
+		for name, value := range override.Headers {
+			merged.Headers[name] = value
+		}
+	}
+	return merged
+}
+
+// validateFetchOptions checks per-crawl fetch options before a crawl is queued
+func validateFetchOptions(opts domain.FetchOptions) error {
+	if opts.ConnectTimeoutMs < 0 || opts.ReadTimeoutMs < 0 || opts.MaxBodyBytes < 0 {
+		return fmt.Errorf("%w: timeouts and max_body_bytes must not be negative", domain.ErrInvalidCrawlOptions)
+	}
+	if opts.ProxyURL != "" {
+		proxyURL, err := url.Parse(opts.ProxyURL)
+		if err != nil || proxyURL.Host == "" {
+			return fmt.Errorf("%w: invalid proxy URL %q", domain.ErrInvalidCrawlOptions, opts.ProxyURL)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("%w: unsupported proxy scheme %q", domain.ErrInvalidCrawlOptions, proxyURL.Scheme)
+		}
+	}
+	for name := range opts.Headers {
+		if name == "" {
+			return fmt.Errorf("%w: header names must not be empty", domain.ErrInvalidCrawlOptions)
+		}
+	}
+	return nil
+}
+
+// maxBytesReadCloser fails with domain.ErrResponseTooLarge once more than remaining bytes are read
+type maxBytesReadCloser struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (r *maxBytesReadCloser) Read(p []byte) (int, error) {
+	if r.remaining < 0 {
+		return 0, domain.ErrResponseTooLarge
+	}
+	// Read one byte more than allowed to detect an oversized body
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+	n, err := r.ReadCloser.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return n + int(r.remaining), domain.ErrResponseTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"strings"
+
+	"backend/domain"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// maxHeadingTextLength caps the stored text of a heading, in runes
+const maxHeadingTextLength = 500
+
+// HeadingOutline is the ordered list of headings of a page with the checks derived from it
+type HeadingOutline struct {
+	Headings   []domain.Heading
+	Counts     map[string]int // Number of headings per tag, e.g. "h2": 3
+	MultipleH1 bool
+	MissingH1  bool
+	LevelSkips int // Headings more than one level deeper than the heading before them
+
+	elements []*goquery.Selection // Element of each heading, for rules that point at it
+}
+
+// buildHeadingOutline collects the h1..h6 headings in document order
+func buildHeadingOutline(doc *goquery.Document) HeadingOutline {
+	outline := HeadingOutline{Counts: make(map[string]int)}
+
+	previousLevel := 0
+	doc.Find("h1, h2, h3, h4, h5, h6").Each(func(i int, heading *goquery.Selection) {
+		tag := goquery.NodeName(heading)
+		level := int(tag[1] - '0')
+
+		text := []rune(strings.Join(strings.Fields(heading.Text()), " "))
+		if len(text) > maxHeadingTextLength {
+			text = text[:maxHeadingTextLength]
+		}
+
+		outline.Headings = append(outline.Headings, domain.Heading{
+			Level:    level,
+			Text:     string(text),
+			Position: i + 1,
+		})
+		outline.elements = append(outline.elements, heading)
+		outline.Counts[tag]++
+
+		if previousLevel > 0 && level > previousLevel+1 {
+			outline.LevelSkips++
+		}
+		previousLevel = level
+	})
+
+	outline.MultipleH1 = outline.Counts["h1"] > 1
+	outline.MissingH1 = outline.Counts["h1"] == 0
+	return outline
+}
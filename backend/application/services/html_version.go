@@ -0,0 +1,247 @@
+package services
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Document modes as defined by the WHATWG HTML parsing algorithm
+const (
+	DocumentModeNoQuirks      = "no-quirks"
+	DocumentModeLimitedQuirks = "limited-quirks"
+	DocumentModeQuirks        = "quirks"
+)
+
+const (
+	htmlVersionUnknown   = "Unknown/Other"
+	htmlVersionNoDoctype = "No DOCTYPE"
+)
+
+// HTMLVersionInfo describes the DOCTYPE of a document and the version it declares
+type HTMLVersionInfo struct {
+	Version      string
+	HasDoctype   bool
+	Name         string
+	PublicID     string
+	SystemID     string
+	DocumentMode string
+}
+
+// htmlVersionCatalog maps normalized public identifiers, without their language suffix, to versions
+var htmlVersionCatalog = map[string]string{
+	"-//ietf//dtd html 2.0":                 "HTML 2.0",
+	"-//ietf//dtd html":                     "HTML 2.0",
+	"-//w3c//dtd html 3.2":                  "HTML 3.2",
+	"-//w3c//dtd html 3.2 final":            "HTML 3.2",
+	"-//w3c//dtd html 4.0":                  "HTML 4.0 Strict",
+	"-//w3c//dtd html 4.0 transitional":     "HTML 4.0 Transitional",
+	"-//w3c//dtd html 4.0 frameset":         "HTML 4.0 Frameset",
+	"-//w3c//dtd html 4.01":                 "HTML 4.01 Strict",
+	"-//w3c//dtd html 4.01 transitional":    "HTML 4.01 Transitional",
+	"-//w3c//dtd html 4.01 frameset":        "HTML 4.01 Frameset",
+	"-//w3c//dtd html 4.01+rdfa 1.1":        "HTML+RDFa 1.1",
+	"-//w3c//dtd xhtml 1.0 strict":          "XHTML 1.0 Strict",
+	"-//w3c//dtd xhtml 1.0 transitional":    "XHTML 1.0 Transitional",
+	"-//w3c//dtd xhtml 1.0 frameset":        "XHTML 1.0 Frameset",
+	"-//w3c//dtd xhtml 1.1":                 "XHTML 1.1",
+	"-//w3c//dtd xhtml 1.1 plus mathml 2.0": "XHTML 1.1 plus MathML 2.0",
+	"-//w3c//dtd xhtml basic 1.0":           "XHTML Basic 1.0",
+	"-//w3c//dtd xhtml basic 1.1":           "XHTML Basic 1.1",
+	"-//w3c//dtd xhtml+rdfa 1.0":            "XHTML+RDFa 1.0",
+	"-//w3c//dtd xhtml+rdfa 1.1":            "XHTML+RDFa 1.1",
+	"-//wapforum//dtd xhtml mobile 1.0":     "XHTML Mobile 1.0",
+	"-//wapforum//dtd xhtml mobile 1.1":     "XHTML Mobile 1.1",
+	"-//wapforum//dtd xhtml mobile 1.2":     "XHTML Mobile 1.2",
+}
+
+// quirksPublicIDPrefixes lists the public identifier prefixes that trigger quirks mode
+var quirksPublicIDPrefixes = []string{
+	"+//silmaril//dtd html pro v0r11 19970101//",
+	"-//as//dtd html 3.0 aswedit + extensions//",
+	"-//advasoft ltd//dtd html 3.0 aswedit + extensions//",
+	"-//ietf//dtd html 2.0 level 1//",
+	"-//ietf//dtd html 2.0 level 2//",
+	"-//ietf//dtd html 2.0 strict level 1//",
+	"-//ietf//dtd html 2.0 strict level 2//",
+	"-//ietf//dtd html 2.0 strict//",
+	"-//ietf//dtd html 2.0//",
+	"-//ietf//dtd html 2.1e//",
+	"-//ietf//dtd html 3.0//",
+	"-//ietf//dtd html 3.2 final//",
+	"-//ietf//dtd html 3.2//",
+	"-//ietf//dtd html 3//",
+	"-//ietf//dtd html level 0//",
+	"-//ietf//dtd html level 1//",
+	"-//ietf//dtd html level 2//",
+	"-//ietf//dtd html level 3//",
+	"-//ietf//dtd html strict level 0//",
+	"-//ietf//dtd html strict level 1//",
+	"-//ietf//dtd html strict level 2//",
+	"-//ietf//dtd html strict level 3//",
+	"-//ietf//dtd html strict//",
+	"-//ietf//dtd html//",
+	"-//metrius//dtd metrius presentational//",
+	"-//microsoft//dtd internet explorer 2.0 html strict//",
+	"-//microsoft//dtd internet explorer 2.0 html//",
+	"-//microsoft//dtd internet explorer 2.0 tables//",
+	"-//microsoft//dtd internet explorer 3.0 html strict//",
+	"-//microsoft//dtd internet explorer 3.0 html//",
+	"-//microsoft//dtd internet explorer 3.0 tables//",
+	"-//netscape comm. corp.//dtd html//",
+	"-//netscape comm. corp.//dtd strict html//",
+	"-//o'reilly and associates//dtd html 2.0//",
+	"-//o'reilly and associates//dtd html extended 1.0//",
+	"-//o'reilly and associates//dtd html extended relaxed 1.0//",
+	"-//sq//dtd html 2.0 hotmetal + extensions//",
+	"-//softquad software//dtd hotmetal pro 6.0::19990601::extensions to html 4.0//",
+	"-//softquad//dtd hotmetal pro 4.0::19971010::extensions to html 4.0//",
+	"-//spyglass//dtd html 2.0 extended//",
+	"-//sun microsystems corp.//dtd hotjava html//",
+	"-//sun microsystems corp.//dtd hotjava strict html//",
+	"-//w3c//dtd html 3 1995-03-24//",
+	"-//w3c//dtd html 3.2 draft//",
+	"-//w3c//dtd html 3.2 final//",
+	"-//w3c//dtd html 3.2//",
+	"-//w3c//dtd html 3.2s draft//",
+	"-//w3c//dtd html 4.0 frameset//",
+	"-//w3c//dtd html 4.0 transitional//",
+	"-//w3c//dtd html experimental 19960712//",
+	"-//w3c//dtd html experimental 970421//",
+	"-//w3c//dtd w3 html//",
+	"-//w3o//dtd w3 html 3.0//",
+	"-//webtechs//dtd mozilla html 2.0//",
+	"-//webtechs//dtd mozilla html//",
+}
+
+// detectHTMLVersion tokenizes the start of the document, reads its DOCTYPE and maps it to a known version
+func detectHTMLVersion(body []byte) HTMLVersionInfo {
+	z := html.NewTokenizer(bytes.NewReader(body))
+	for {
+		switch z.Next() {
+		case html.DoctypeToken:
+			return parseDoctype(string(z.Text()))
+		case html.CommentToken:
+			continue
+		case html.TextToken:
+			// Only whitespace may precede the DOCTYPE
+			if strings.TrimSpace(strings.TrimPrefix(string(z.Text()), "\ufeff")) == "" {
+				continue
+			}
+		}
+
+		// Any element, text or the end of input means there is no DOCTYPE
+		return HTMLVersionInfo{
+			Version:      htmlVersionNoDoctype,
+			DocumentMode: DocumentModeQuirks,
+		}
+	}
+}
+
+// parseDoctype parses the contents of a DOCTYPE token, e.g. `html PUBLIC "-//W3C//DTD HTML 4.01//EN" "..."`
+func parseDoctype(doctype string) HTMLVersionInfo {
+	info := HTMLVersionInfo{HasDoctype: true}
+
+	rest := strings.TrimSpace(doctype)
+	nameEnd := strings.IndexFunc(rest, isDoctypeSpace)
+	if nameEnd < 0 {
+		nameEnd = len(rest)
+	}
+	info.Name = strings.ToLower(rest[:nameEnd])
+	rest = strings.TrimSpace(rest[nameEnd:])
+
+	hasPublicID, hasSystemID := false, false
+	keyword := strings.ToUpper(rest[:min(len(rest), 6)])
+	switch keyword {
+	case "PUBLIC":
+		rest = strings.TrimSpace(rest[6:])
+		info.PublicID, rest, hasPublicID = readQuotedIdentifier(rest)
+		if hasPublicID {
+			info.SystemID, _, hasSystemID = readQuotedIdentifier(strings.TrimSpace(rest))
+		}
+	case "SYSTEM":
+		info.SystemID, _, hasSystemID = readQuotedIdentifier(strings.TrimSpace(rest[6:]))
+	}
+
+	info.Version = lookupHTMLVersion(info, hasPublicID, hasSystemID)
+	info.DocumentMode = documentMode(info, hasSystemID)
+	return info
+}
+
+// readQuotedIdentifier reads a single- or double-quoted identifier and returns the remaining input
+func readQuotedIdentifier(s string) (string, string, bool) {
+	if s == "" || (s[0] != '"' && s[0] != '\'') {
+		return "", s, false
+	}
+	end := strings.IndexByte(s[1:], s[0])
+	if end < 0 {
+		return s[1:], "", true
+	}
+	return s[1 : end+1], s[end+2:], true
+}
+
+func lookupHTMLVersion(info HTMLVersionInfo, hasPublicID, hasSystemID bool) string {
+	if info.Name != "html" {
+		return htmlVersionUnknown
+	}
+
+	if !hasPublicID {
+		if !hasSystemID || strings.EqualFold(info.SystemID, "about:legacy-compat") {
+			return "HTML5"
+		}
+		return htmlVersionUnknown
+	}
+
+	publicID := normalizePublicID(info.PublicID)
+	if i := strings.LastIndex(publicID, "//"); i > 0 {
+		if version, ok := htmlVersionCatalog[publicID[:i]]; ok {
+			return version
+		}
+	}
+	if version, ok := htmlVersionCatalog[publicID]; ok {
+		return version
+	}
+	return htmlVersionUnknown
+}
+
+// documentMode determines the document mode a browser would render the document in
+func documentMode(info HTMLVersionInfo, hasSystemID bool) string {
+	publicID := normalizePublicID(info.PublicID)
+	systemID := strings.ToLower(info.SystemID)
+
+	if info.Name != "html" ||
+		publicID == "-//w3o//dtd w3 html strict 3.0//en//" ||
+		publicID == "-/w3c/dtd html 4.0 transitional/en" ||
+		publicID == "html" ||
+		systemID == "http://www.ibm.com/data/dtd/v11/ibmxhtml1-transitional.dtd" {
+		return DocumentModeQuirks
+	}
+	for _, prefix := range quirksPublicIDPrefixes {
+		if strings.HasPrefix(publicID, prefix) {
+			return DocumentModeQuirks
+		}
+	}
+
+	html401Loose := strings.HasPrefix(publicID, "-//w3c//dtd html 4.01 frameset//") ||
+		strings.HasPrefix(publicID, "-//w3c//dtd html 4.01 transitional//")
+	if html401Loose && !hasSystemID {
+		return DocumentModeQuirks
+	}
+	if html401Loose ||
+		strings.HasPrefix(publicID, "-//w3c//dtd xhtml 1.0 frameset//") ||
+		strings.HasPrefix(publicID, "-//w3c//dtd xhtml 1.0 transitional//") {
+		return DocumentModeLimitedQuirks
+	}
+
+	return DocumentModeNoQuirks
+}
+
+// normalizePublicID lowercases the identifier and collapses runs of whitespace
+func normalizePublicID(publicID string) string {
+	return strings.ToLower(strings.Join(strings.Fields(publicID), " "))
+}
+
+func isDoctypeSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f'
+}
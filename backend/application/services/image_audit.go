@@ -0,0 +1,114 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+
+	"backend/domain"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// maxDataURLMediaTypeLength bounds the media type kept of a data: URL that is missing its comma
+const maxDataURLMediaTypeLength = 255
+
+// ImageAudit lists the images of a page and summarizes their alt text and dimension problems
+type ImageAudit struct {
+	Images            []domain.Image
+	ImageCount        int // <img> elements
+	MissingAlt        int // <img> elements without an alt attribute
+	EmptyAlt          int // <img> elements with an empty alt attribute
+	MissingDimensions int // <img> elements without width or height
+}
+
+// auditImages inventories every <img> src, every <picture> <source> and every srcset candidate of the document
+func auditImages(doc *goquery.Document, pageURL *url.URL) ImageAudit {
+	var audit ImageAudit
+
+	doc.Find("img").Each(func(i int, img *goquery.Selection) {
+		alt, hasAlt := img.Attr("alt")
+		width := strings.TrimSpace(img.AttrOr("width", ""))
+		height := strings.TrimSpace(img.AttrOr("height", ""))
+
+		audit.ImageCount++
+		switch {
+		case !hasAlt:
+			audit.MissingAlt++
+		case strings.TrimSpace(alt) == "":
+			audit.EmptyAlt++
+		}
+		if width == "" || height == "" {
+			audit.MissingDimensions++
+		}
+
+		// Sources of a <picture> share the alt text and dimensions of its <img>
+		image := domain.Image{
+			Element:  "img",
+			HasAlt:   hasAlt,
+			Alt:      strings.TrimSpace(alt),
+			Width:    width,
+			Height:   height,
+			ByteSize: -1,
+		}
+
+		if src := strings.TrimSpace(img.AttrOr("src", "")); src != "" {
+			audit.addImage(image, pageURL, src, "src", "")
+		}
+		audit.addSrcset(image, pageURL, img.AttrOr("srcset", ""))
+
+		if picture := img.Parent(); goquery.NodeName(picture) == "picture" {
+			picture.ChildrenFiltered("source").Each(func(j int, source *goquery.Selection) {
+				sourceImage := image
+				sourceImage.Element = "source"
+				audit.addSrcset(sourceImage, pageURL, source.AttrOr("srcset", ""))
+			})
+		}
+	})
+
+	return audit
+}
+
+func (a *ImageAudit) addImage(image domain.Image, pageURL *url.URL, rawURL, attribute, descriptor string) {
+	resolved, err := pageURL.Parse(rawURL)
+	switch {
+	case isDataURL(rawURL):
+		// Inline images are recorded by media type; their payload easily outgrows the stored URL
+		image.URL = "data:" + dataURLMediaType(rawURL)
+	case err != nil:
+		image.URL = rawURL
+		image.Broken = true
+		image.Error = "invalid URL"
+	default:
+		image.URL = resolved.String()
+	}
+	image.Attribute = attribute
+	image.Descriptor = descriptor
+	a.Images = append(a.Images, image)
+}
+
+// addSrcset adds every candidate of a srcset, e.g. "a.jpg 1x, b.jpg 2x" or "s.jpg 480w, l.jpg 1080w"
+func (a *ImageAudit) addSrcset(image domain.Image, pageURL *url.URL, srcset string) {
+	for _, candidate := range parseSrcset(srcset) {
+		descriptor := ""
+		if len(candidate.Descriptors) > 0 {
+			descriptor = candidate.Descriptors[0]
+		}
+		a.addImage(image, pageURL, candidate.URL, "srcset", descriptor)
+	}
+}
+
+func isDataURL(rawURL string) bool {
+	return len(rawURL) >= len("data:") && strings.EqualFold(rawURL[:len("data:")], "data:")
+}
+
+// dataURLMediaType returns the media type of a data: URL without its parameters, e.g. image/png
+func dataURLMediaType(rawURL string) string {
+	mediaType := rawURL[len("data:"):]
+	if end := strings.IndexAny(mediaType, ";,"); end >= 0 {
+		mediaType = mediaType[:end]
+	}
+	if len(mediaType) > maxDataURLMediaTypeLength {
+		mediaType = mediaType[:maxDataURLMediaTypeLength]
+	}
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"backend/domain"
+)
+
+// LinkChecker verifies that links are reachable using concurrent HEAD/GET requests
+type LinkChecker struct {
+	fetcher     Fetcher
+	concurrency int           // Maximum number of links checked at the same time
+	perHost     int           // Maximum number of links checked at the same time against a single host
+	timeout     time.Duration // Timeout for a single link check
+}
+
+// NewLinkChecker creates a new LinkChecker
+func NewLinkChecker(fetcher Fetcher, concurrency, perHost int, timeout time.Duration) *LinkChecker {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if perHost < 1 {
+		perHost = 1
+	}
+	return &LinkChecker{
+		fetcher:     fetcher,
+		concurrency: concurrency,
+		perHost:     perHost,
+		timeout:     timeout,
+	}
+}
+
+// ResourceStatus is the outcome of requesting a URL
+type ResourceStatus struct {
+	URL        *url.URL
+	StatusCode int   // 0 when the request failed before a response was received
+	ByteSize   int64 // -1 when unknown
+	Err        error
+}
+
+// Broken reports whether the request failed or the server answered with a 4xx/5xx status code
+func (s ResourceStatus) Broken() bool {
+	return s.Err != nil || s.StatusCode >= 400
+}
+
+// Check requests every link with opts and returns the ones that failed or answered with a 4xx/5xx status code
+func (c *LinkChecker) Check(ctx context.Context, links []*url.URL, opts domain.FetchOptions) []domain.BrokenLink {
+	var broken []domain.BrokenLink
+	for _, status := range c.Probe(ctx, links, opts, false) {
+		if !status.Broken() {
+			continue
+		}
+
+		brokenLink := domain.BrokenLink{
+			URL:        status.URL.String(),
+			StatusCode: status.StatusCode,
+		}
+		if status.Err != nil {
+			brokenLink.Error = status.Err.Error()
+		} else {
+			brokenLink.Error = http.StatusText(status.StatusCode)
+		}
+		broken = append(broken, brokenLink)
+	}
+	return broken
+}
+
+// Probe requests every link with opts and returns their statuses in the order of links.
+// With measure set, a body without a Content-Length is downloaded to determine its size.
+// Links that were not requested because ctx was cancelled are left out.
+func (c *LinkChecker) Probe(ctx context.Context, links []*url.URL, opts domain.FetchOptions, measure bool) []ResourceStatus {
+	var wg sync.WaitGroup
+	statuses := make([]ResourceStatus, len(links))
+	checked := make([]bool, len(links))
+
+	hostSlots := make(map[string]chan struct{})
+	slots := make(chan struct{}, c.concurrency)
+
+	for i, link := range links {
+		sem, ok := hostSlots[link.Host]
+		if !ok {
+			sem = make(chan struct{}, c.perHost)
+			hostSlots[link.Host] = sem
+		}
+
+		wg.Add(1)
+		go func(i int, link *url.URL, sem chan struct{}) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-slots }()
+
+			statuses[i] = c.checkLink(ctx, link, opts, measure)
+			checked[i] = true
+		}(i, link, sem)
+	}
+
+	wg.Wait()
+
+	probed := make([]ResourceStatus, 0, len(links))
+	for i, status := range statuses {
+		if checked[i] {
+			probed = append(probed, status)
+		}
+	}
+	return probed
+}
+
+// checkLink sends a HEAD request and falls back to GET when the server rejects or fails it,
+// or when the size is to be measured but unknown
+func (c *LinkChecker) checkLink(ctx context.Context, link *url.URL, opts domain.FetchOptions, measure bool) ResourceStatus {
+	status := c.request(ctx, http.MethodHead, link, opts, false)
+	if !status.Broken() && (!measure || status.ByteSize >= 0) {
+		return status
+	}
+	if ctx.Err() != nil {
+		return status
+	}
+
+	// Many servers do not implement HEAD properly, so confirm the failure with GET
+	return c.request(ctx, http.MethodGet, link, opts, measure)
+}
+
+func (c *LinkChecker) request(ctx context.Context, method string, link *url.URL, opts domain.FetchOptions, measure bool) ResourceStatus {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	status := ResourceStatus{URL: link, ByteSize: -1}
+	res, err := c.fetcher.Fetch(ctx, method, link.String(), opts)
+	if err != nil {
+		status.Err = err
+		return status
+	}
+	defer res.Body.Close()
+
+	status.StatusCode = res.StatusCode
+	status.ByteSize = res.ContentLength
+	if measure && method == http.MethodGet && res.ContentLength < 0 {
+		if n, err := io.Copy(io.Discard, res.Body); err == nil {
+			status.ByteSize = n
+		}
+		return status
+	}
+
+	// Drain a little of the body so the connection can be reused
+	io.CopyN(io.Discard, res.Body, 4096)
+
+	return status
+}
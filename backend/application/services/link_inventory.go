@@ -0,0 +1,69 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+
+	"backend/domain"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/publicsuffix"
+)
+
+// inventoryLink describes an <a> element for the link inventory
+func inventoryLink(anchor *goquery.Selection, href string, resolved, pageURL *url.URL) domain.Link {
+	link := domain.Link{
+		URL:        resolved.String(),
+		Href:       href,
+		AnchorText: anchorText(anchor),
+		Rel:        strings.Join(strings.Fields(strings.ToLower(anchor.AttrOr("rel", ""))), " "),
+		Target:     strings.TrimSpace(anchor.AttrOr("target", "")),
+	}
+
+	switch {
+	case strings.HasPrefix(strings.TrimSpace(href), "#"):
+		link.Scheme = domain.LinkSchemeFragment
+		link.Classification = domain.LinkInternal
+	case resolved.Scheme == "http" || resolved.Scheme == "https":
+		link.Scheme = resolved.Scheme
+		link.Classification = classifyHost(resolved, pageURL)
+	case resolved.Scheme == "mailto", resolved.Scheme == "tel", resolved.Scheme == "javascript":
+		link.Scheme = resolved.Scheme
+	default:
+		link.Scheme = domain.LinkSchemeOther
+	}
+	return link
+}
+
+// classifyHost tells whether a link stays on the page's host, moves to another host of the same site or leaves it
+func classifyHost(link, pageURL *url.URL) string {
+	linkHost := strings.ToLower(link.Hostname())
+	pageHost := strings.ToLower(pageURL.Hostname())
+	if linkHost == pageHost {
+		return domain.LinkInternal
+	}
+
+	linkSite, err := publicsuffix.EffectiveTLDPlusOne(linkHost)
+	if err != nil {
+		return domain.LinkExternal
+	}
+	pageSite, err := publicsuffix.EffectiveTLDPlusOne(pageHost)
+	if err != nil || linkSite != pageSite {
+		return domain.LinkExternal
+	}
+	return domain.LinkSubdomain
+}
+
+// anchorText returns the visible text of a link, falling back to its accessible name for image links
+func anchorText(anchor *goquery.Selection) string {
+	if text := strings.Join(strings.Fields(anchor.Text()), " "); text != "" {
+		return text
+	}
+	if label := strings.TrimSpace(anchor.AttrOr("aria-label", "")); label != "" {
+		return label
+	}
+	if alt := strings.TrimSpace(anchor.Find("img[alt]").First().AttrOr("alt", "")); alt != "" {
+		return alt
+	}
+	return strings.TrimSpace(anchor.AttrOr("title", ""))
+}
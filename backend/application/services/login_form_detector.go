@@ -0,0 +1,104 @@
+package services
+
+import (
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// loginFormThreshold is the minimum confidence for a form to be reported as a login form
+const loginFormThreshold = 0.5
+
+var (
+	usernameFieldPattern = regexp.MustCompile(`(?i)user|email|e-mail|login|account|identifier`)
+	authActionPattern    = regexp.MustCompile(`(?i)log-?in|sign-?in|logon|auth|session|sso`)
+	loginButtonPattern   = regexp.MustCompile(`(?i)log\s*-?\s*in|sign\s*-?\s*in|log\s*on|anmelden|connexion`)
+)
+
+// LoginFormDetection describes the form on a page that most likely is a login form
+type LoginFormDetection struct {
+	Found      bool
+	Confidence float64
+	ActionURL  string
+}
+
+// detectLoginForm scores every <form> in the document and returns the best match
+func detectLoginForm(doc *goquery.Document, pageURL *url.URL) LoginFormDetection {
+	var best LoginFormDetection
+
+	doc.Find("form").Each(func(i int, form *goquery.Selection) {
+		score := scoreLoginForm(form)
+		if score <= best.Confidence {
+			return
+		}
+
+		best.Confidence = score
+		best.ActionURL = pageURL.String()
+		if action, exists := form.Attr("action"); exists && strings.TrimSpace(action) != "" {
+			if resolved, err := pageURL.Parse(strings.TrimSpace(action)); err == nil {
+				best.ActionURL = resolved.String()
+			}
+		}
+	})
+
+	best.Found = best.Confidence >= loginFormThreshold
+	return best
+}
+
+// scoreLoginForm returns a confidence between 0 and 1 that the form is a login form
+func scoreLoginForm(form *goquery.Selection) float64 {
+	score := 0.0
+
+	// A single password field is the strongest signal; two or more usually mean sign-up or password change
+	passwordFields := form.Find(`input[type="password" i]`).Length()
+	switch {
+	case passwordFields == 1:
+		score += 0.5
+	case passwordFields > 1:
+		score += 0.3
+	}
+
+	hasUsernameField := false
+	form.Find("input").EachWithBreak(func(i int, input *goquery.Selection) bool {
+		inputType := strings.ToLower(input.AttrOr("type", "text"))
+		if inputType == "email" {
+			hasUsernameField = true
+			return false
+		}
+		if inputType != "text" && inputType != "tel" {
+			return true
+		}
+		attrs := input.AttrOr("name", "") + " " + input.AttrOr("id", "") + " " +
+			input.AttrOr("autocomplete", "") + " " + input.AttrOr("placeholder", "")
+		if usernameFieldPattern.MatchString(attrs) {
+			hasUsernameField = true
+			return false
+		}
+		return true
+	})
+	if hasUsernameField {
+		score += 0.2
+	}
+
+	if authActionPattern.MatchString(form.AttrOr("action", "") + " " + form.AttrOr("id", "") + " " + form.AttrOr("class", "")) {
+		score += 0.15
+	}
+
+	hasLoginButton := false
+	form.Find(`button, input[type="submit" i]`).EachWithBreak(func(i int, button *goquery.Selection) bool {
+		label := button.Text() + " " + button.AttrOr("value", "") + " " + button.AttrOr("name", "")
+		if loginButtonPattern.MatchString(label) {
+			hasLoginButton = true
+			return false
+		}
+		return true
+	})
+	if hasLoginButton {
+		score += 0.15
+	}
+
+	return math.Min(math.Round(score*100)/100, 1)
+}
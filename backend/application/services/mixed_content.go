@@ -0,0 +1,97 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+
+	"backend/domain"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// mixedContentSource is an element attribute that makes the browser load or submit to a URL
+type mixedContentSource struct {
+	selector    string
+	attribute   string
+	contentType string
+}
+
+// mixedContentSources follows the W3C Mixed Content classification: only images and media are passive
+var mixedContentSources = []mixedContentSource{
+	{`script[src]`, "src", domain.MixedContentActive},
+	{`link[href]`, "href", domain.MixedContentActive}, // Stylesheets and preloads; icons are passive, see linkContentType
+	{`iframe[src]`, "src", domain.MixedContentActive},
+	{`frame[src]`, "src", domain.MixedContentActive},
+	{`object[data]`, "data", domain.MixedContentActive},
+	{`embed[src]`, "src", domain.MixedContentActive},
+	{`form[action]`, "action", domain.MixedContentActive},
+	{`button[formaction]`, "formaction", domain.MixedContentActive},
+	{`input[formaction]`, "formaction", domain.MixedContentActive},
+	{`img[src]`, "src", domain.MixedContentPassive},
+	{`img[srcset]`, "srcset", domain.MixedContentPassive},
+	{`picture > source[srcset]`, "srcset", domain.MixedContentPassive},
+	{`input[type="image" i][src]`, "src", domain.MixedContentPassive},
+	{`audio[src]`, "src", domain.MixedContentPassive},
+	{`video[src]`, "src", domain.MixedContentPassive},
+	{`video[poster]`, "poster", domain.MixedContentPassive},
+	{`audio > source[src], video > source[src]`, "src", domain.MixedContentPassive},
+	{`track[src]`, "src", domain.MixedContentPassive},
+}
+
+// detectMixedContent lists the subresources and form targets of an HTTPS page that use plain HTTP.
+// Pages that are not served over HTTPS have no mixed content.
+func detectMixedContent(doc *goquery.Document, pageURL *url.URL) []domain.MixedContent {
+	var found []domain.MixedContent
+	if pageURL.Scheme != "https" {
+		return found
+	}
+
+	for _, source := range mixedContentSources {
+		doc.Find(source.selector).Each(func(i int, element *goquery.Selection) {
+			contentType := source.contentType
+			if goquery.NodeName(element) == "link" {
+				var ok bool
+				if contentType, ok = linkContentType(element); !ok {
+					return
+				}
+			}
+
+			var rawURLs []string
+			if source.attribute == "srcset" {
+				for _, candidate := range parseSrcset(element.AttrOr("srcset", "")) {
+					rawURLs = append(rawURLs, candidate.URL)
+				}
+			} else {
+				rawURLs = append(rawURLs, element.AttrOr(source.attribute, ""))
+			}
+
+			for _, rawURL := range rawURLs {
+				resolved, err := pageURL.Parse(strings.TrimSpace(rawURL))
+				if err != nil || resolved.Scheme != "http" {
+					continue
+				}
+				found = append(found, domain.MixedContent{
+					URL:       resolved.String(),
+					Element:   goquery.NodeName(element),
+					Attribute: source.attribute,
+					Type:      contentType,
+				})
+			}
+		})
+	}
+	return found
+}
+
+// linkContentType classifies a <link> by its rel tokens; links that load nothing, e.g. canonical, are skipped
+func linkContentType(link *goquery.Selection) (string, bool) {
+	contentType, loads := "", false
+	for _, rel := range strings.Fields(strings.ToLower(link.AttrOr("rel", ""))) {
+		switch rel {
+		case "stylesheet", "preload", "modulepreload", "prefetch", "manifest":
+			return domain.MixedContentActive, true
+		case "icon", "apple-touch-icon":
+			contentType, loads = domain.MixedContentPassive, true
+		}
+	}
+	return contentType, loads
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"backend/domain"
+)
+
+const (
+	robotsMaxBytes     = 500 * 1024 // RFC 9309 requires parsing at least 500 KiB
+	robotsCacheTTL     = time.Hour
+	robotsErrorTTL     = time.Minute
+	robotsMaxCrawlWait = time.Minute // Upper bound for a single Crawl-delay
+)
+
+// robotsRule is a single Allow or Disallow line of the group that applies to our user agent
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// robotsPolicy holds the rules of a robots.txt that apply to our user agent
+type robotsPolicy struct {
+	disallowAll bool // Set when robots.txt could not be reached (5xx or network error)
+	rules       []robotsRule
+	crawlDelay  time.Duration
+}
+
+type robotsCacheEntry struct {
+	policy    *robotsPolicy
+	expiresAt time.Time
+}
+
+// RobotsChecker fetches, parses and caches robots.txt per host and enforces Crawl-delay between requests
+type RobotsChecker struct {
+	fetcher   Fetcher
+	userAgent string // Rules are evaluated for this user agent, even when a crawl overrides the one it sends
+
+	mu        sync.Mutex
+	cache     map[string]robotsCacheEntry // Keyed by scheme://host
+	nextFetch map[string]time.Time        // Earliest time of the next request per host
+}
+
+// NewRobotsChecker creates a new RobotsChecker that evaluates rules for the given user agent
+func NewRobotsChecker(fetcher Fetcher, userAgent string) *RobotsChecker {
+	return &RobotsChecker{
+		fetcher:   fetcher,
+		userAgent: userAgent,
+		cache:     make(map[string]robotsCacheEntry),
+		nextFetch: make(map[string]time.Time),
+	}
+}
+
+// UserAgent returns the user agent the crawler identifies itself with
+func (c *RobotsChecker) UserAgent() string {
+	return c.userAgent
+}
+
+// Allowed reports whether robots.txt permits fetching the URL and returns the host's Crawl-delay.
+// A robots.txt that is not cached yet is fetched with opts.
+func (c *RobotsChecker) Allowed(ctx context.Context, u *url.URL, opts domain.FetchOptions) (bool, time.Duration) {
+	policy := c.policy(ctx, u, opts)
+	if policy.disallowAll {
+		return false, 0
+	}
+
+	target := u.EscapedPath()
+	if target == "" {
+		target = "/"
+	}
+	if target == "/robots.txt" {
+		return true, policy.crawlDelay
+	}
+	if u.RawQuery != "" {
+		target += "?" + u.RawQuery
+	}
+
+	// The longest matching rule wins; on a tie Allow wins
+	allowed, matchLength := true, -1
+	for _, rule := range policy.rules {
+		if !matchRobotsPattern(rule.pattern, target) {
+			continue
+		}
+		if len(rule.pattern) > matchLength || (len(rule.pattern) == matchLength && rule.allow) {
+			allowed, matchLength = rule.allow, len(rule.pattern)
+		}
+	}
+	return allowed, policy.crawlDelay
+}
+
+// Wait blocks until the host may be requested again according to its Crawl-delay
+func (c *RobotsChecker) Wait(ctx context.Context, u *url.URL, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+	delay = min(delay, robotsMaxCrawlWait)
+
+	host := strings.ToLower(u.Host)
+	c.mu.Lock()
+	now := time.Now()
+	at := c.nextFetch[host]
+	if at.Before(now) {
+		at = now
+	}
+	c.nextFetch[host] = at.Add(delay)
+	c.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// policy returns the cached robots policy of the URL's host, fetching it when missing or expired
+func (c *RobotsChecker) policy(ctx context.Context, u *url.URL, opts domain.FetchOptions) *robotsPolicy {
+	key := strings.ToLower(u.Scheme + "://" + u.Host)
+
+	c.mu.Lock()
+	entry, ok := c.cache[key]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.policy
+	}
+
+	policy, ttl := c.fetch(ctx, key+"/robots.txt", opts)
+	if ctx.Err() != nil {
+		// Do not cache the outcome of an aborted fetch
+		return policy
+	}
+
+	c.mu.Lock()
+	c.cache[key] = robotsCacheEntry{policy: policy, expiresAt: time.Now().Add(ttl)}
+	c.mu.Unlock()
+	return policy
+}
+
+// fetch downloads and parses a robots.txt following RFC 9309:
+// 4xx means no restrictions, 5xx or an unreachable server means everything is disallowed
+func (c *RobotsChecker) fetch(ctx context.Context, robotsURL string, opts domain.FetchOptions) (*robotsPolicy, time.Duration) {
+	res, err := c.fetcher.Fetch(ctx, http.MethodGet, robotsURL, opts)
+	if err != nil {
+		return &robotsPolicy{disallowAll: true}, robotsErrorTTL
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode >= 500:
+		return &robotsPolicy{disallowAll: true}, robotsErrorTTL
+	case res.StatusCode >= 400:
+		return &robotsPolicy{}, robotsCacheTTL
+	}
+
+	return parseRobots(io.LimitReader(res.Body, robotsMaxBytes), c.userAgent), robotsCacheTTL
+}
+
+// parseRobots parses a robots.txt and keeps the rules of the groups that apply to the user agent.
+// Groups naming the user agent's product token take precedence over the "*" group.
+func parseRobots(r io.Reader, userAgent string) *robotsPolicy {
+	token := strings.ToLower(userAgent)
+	if i := strings.IndexAny(token, "/ "); i >= 0 {
+		token = token[:i]
+	}
+
+	var specific, wildcard robotsPolicy
+	var hasSpecific bool
+
+	var groupAgents []string
+	inRules := false // Whether the current group has started its rules
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if key == "user-agent" {
+			if inRules {
+				groupAgents, inRules = nil, false
+			}
+			groupAgents = append(groupAgents, strings.ToLower(value))
+			continue
+		}
+		if key != "allow" && key != "disallow" && key != "crawl-delay" {
+			continue
+		}
+		inRules = true
+
+		for _, agent := range groupAgents {
+			var target *robotsPolicy
+			switch {
+			case agent == token:
+				target, hasSpecific = &specific, true
+			case agent == "*":
+				target = &wildcard
+			default:
+				continue
+			}
+
+			switch key {
+			case "allow", "disallow":
+				if value != "" {
+					target.rules = append(target.rules, robotsRule{allow: key == "allow", pattern: value})
+				}
+			case "crawl-delay":
+				if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+					target.crawlDelay = time.Duration(seconds * float64(time.Second))
+				}
+			}
+		}
+	}
+
+	if hasSpecific {
+		return &specific
+	}
+	return &wildcard
+}
+
+// matchRobotsPattern matches a path against a robots.txt pattern supporting "*" and a trailing "$"
+func matchRobotsPattern(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	if anchored {
+		pattern = pattern[:len(pattern)-1]
+	}
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	pos := len(parts[0])
+
+	for i, part := range parts[1:] {
+		last := i == len(parts)-2
+		if last && anchored {
+			return len(path)-pos >= len(part) && strings.HasSuffix(path, part)
+		}
+		idx := strings.Index(path[pos:], part)
+		if idx < 0 {
+			return false
+		}
+		pos += idx + len(part)
+	}
+
+	if anchored && len(parts) == 1 {
+		return pos == len(path)
+	}
+	return true
+}
@@ -0,0 +1,302 @@
+package services
+
+import (
+	"crypto/tls"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/domain"
+)
+
+// Names of the checks of a security report
+const (
+	CheckTLSVersion         = "tls-version"
+	CheckCertificateExpiry  = "certificate-expiry"
+	CheckHSTS               = "strict-transport-security"
+	CheckCSP                = "content-security-policy"
+	CheckFrameOptions       = "x-frame-options"
+	CheckContentTypeOptions = "x-content-type-options"
+	CheckReferrerPolicy     = "referrer-policy"
+	CheckPermissionsPolicy  = "permissions-policy"
+	CheckCookieFlags        = "cookie-flags"
+)
+
+// securityCheckWeights are the points each check contributes to the score of 100
+var securityCheckWeights = map[string]int{
+	CheckTLSVersion:         15,
+	CheckCertificateExpiry:  15,
+	CheckHSTS:               15,
+	CheckCSP:                15,
+	CheckFrameOptions:       10,
+	CheckContentTypeOptions: 10,
+	CheckReferrerPolicy:     5,
+	CheckPermissionsPolicy:  5,
+	CheckCookieFlags:        10,
+}
+
+const (
+	hstsRecommendedMaxAge = 180 * 24 * 60 * 60 // Seconds
+	certificateWarnDays   = 30
+)
+
+// auditSecurity inspects the TLS connection and security headers of a response and scores the result.
+// now is the reference time for certificate expiry.
+func auditSecurity(res *http.Response, now time.Time) domain.SecurityReport {
+	report := domain.SecurityReport{
+		Checks:  []domain.SecurityCheck{},
+		Cookies: []domain.CookieAudit{},
+	}
+	isHTTPS := res.TLS != nil
+	if isHTTPS {
+		report.TLS = tlsInfo(res.TLS, now)
+	}
+
+	check := func(name, status, value, format string, args ...any) {
+		report.Checks = append(report.Checks, domain.SecurityCheck{
+			Name:    name,
+			Status:  status,
+			Weight:  securityCheckWeights[name],
+			Value:   value,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	// TLS connection
+	switch {
+	case !isHTTPS:
+		check(CheckTLSVersion, domain.SecurityCheckFail, "", "Page is not served over HTTPS")
+	case res.TLS.Version < tls.VersionTLS12:
+		check(CheckTLSVersion, domain.SecurityCheckFail, report.TLS.Version, "%s is deprecated, use TLS 1.2 or later", report.TLS.Version)
+	default:
+		check(CheckTLSVersion, domain.SecurityCheckPass, report.TLS.Version, "Connection uses %s", report.TLS.Version)
+	}
+
+	switch {
+	case !isHTTPS || len(report.TLS.Certificates) == 0:
+		check(CheckCertificateExpiry, domain.SecurityCheckFail, "", "No certificate was presented")
+	case report.TLS.Certificates[0].DaysRemaining < 0:
+		check(CheckCertificateExpiry, domain.SecurityCheckFail, "", "Certificate expired on %s", report.TLS.Certificates[0].NotAfter.Format(time.DateOnly))
+	case report.TLS.Certificates[0].DaysRemaining < certificateWarnDays:
+		check(CheckCertificateExpiry, domain.SecurityCheckWarn, "", "Certificate expires in %d days", report.TLS.Certificates[0].DaysRemaining)
+	default:
+		check(CheckCertificateExpiry, domain.SecurityCheckPass, "", "Certificate expires in %d days", report.TLS.Certificates[0].DaysRemaining)
+	}
+
+	// Security headers
+	hsts := res.Header.Get("Strict-Transport-Security")
+	switch {
+	case hsts == "":
+		check(CheckHSTS, domain.SecurityCheckFail, hsts, "Header is missing")
+	case !isHTTPS:
+		check(CheckHSTS, domain.SecurityCheckFail, hsts, "Browsers ignore the header on plain HTTP responses")
+	case hstsMaxAge(hsts) < hstsRecommendedMaxAge:
+		check(CheckHSTS, domain.SecurityCheckWarn, hsts, "max-age is below the recommended %d seconds", hstsRecommendedMaxAge)
+	default:
+		check(CheckHSTS, domain.SecurityCheckPass, hsts, "Header is set")
+	}
+
+	csp := res.Header.Get("Content-Security-Policy")
+	switch {
+	case csp == "" && res.Header.Get("Content-Security-Policy-Report-Only") != "":
+		check(CheckCSP, domain.SecurityCheckWarn, res.Header.Get("Content-Security-Policy-Report-Only"), "Policy is only reported, not enforced")
+	case csp == "":
+		check(CheckCSP, domain.SecurityCheckFail, csp, "Header is missing")
+	case strings.Contains(csp, "'unsafe-inline'") || strings.Contains(csp, "'unsafe-eval'"):
+		check(CheckCSP, domain.SecurityCheckWarn, csp, "Policy allows 'unsafe-inline' or 'unsafe-eval'")
+	default:
+		check(CheckCSP, domain.SecurityCheckPass, csp, "Header is set")
+	}
+
+	// frame-ancestors supersedes X-Frame-Options in browsers that support CSP level 2
+	frameOptions := res.Header.Get("X-Frame-Options")
+	switch strings.ToUpper(strings.TrimSpace(frameOptions)) {
+	case "DENY", "SAMEORIGIN":
+		check(CheckFrameOptions, domain.SecurityCheckPass, frameOptions, "Header is set")
+	case "":
+		if hasCSPDirective(csp, "frame-ancestors") {
+			check(CheckFrameOptions, domain.SecurityCheckPass, frameOptions, "Framing is restricted by CSP frame-ancestors")
+		} else {
+			check(CheckFrameOptions, domain.SecurityCheckFail, frameOptions, "Header is missing")
+		}
+	default:
+		check(CheckFrameOptions, domain.SecurityCheckWarn, frameOptions, "Value should be DENY or SAMEORIGIN")
+	}
+
+	contentTypeOptions := res.Header.Get("X-Content-Type-Options")
+	switch {
+	case strings.EqualFold(strings.TrimSpace(contentTypeOptions), "nosniff"):
+		check(CheckContentTypeOptions, domain.SecurityCheckPass, contentTypeOptions, "Header is set")
+	case contentTypeOptions == "":
+		check(CheckContentTypeOptions, domain.SecurityCheckFail, contentTypeOptions, "Header is missing")
+	default:
+		check(CheckContentTypeOptions, domain.SecurityCheckFail, contentTypeOptions, "Value must be nosniff")
+	}
+
+	// Browsers fall back to strict-origin-when-cross-origin, so a missing header is only a warning
+	referrerPolicy := res.Header.Get("Referrer-Policy")
+	switch effectiveReferrerPolicy(referrerPolicy) {
+	case "no-referrer", "same-origin", "strict-origin", "strict-origin-when-cross-origin":
+		check(CheckReferrerPolicy, domain.SecurityCheckPass, referrerPolicy, "Header is set")
+	case "":
+		check(CheckReferrerPolicy, domain.SecurityCheckWarn, referrerPolicy, "Header is missing")
+	default:
+		check(CheckReferrerPolicy, domain.SecurityCheckWarn, referrerPolicy, "Policy may leak URLs to other origins")
+	}
+
+	permissionsPolicy := res.Header.Get("Permissions-Policy")
+	switch {
+	case permissionsPolicy != "":
+		check(CheckPermissionsPolicy, domain.SecurityCheckPass, permissionsPolicy, "Header is set")
+	case res.Header.Get("Feature-Policy") != "":
+		check(CheckPermissionsPolicy, domain.SecurityCheckWarn, res.Header.Get("Feature-Policy"), "Feature-Policy is deprecated, use Permissions-Policy")
+	default:
+		check(CheckPermissionsPolicy, domain.SecurityCheckFail, permissionsPolicy, "Header is missing")
+	}
+
+	// Cookies
+	var insecure, unprotected []string
+	for _, cookie := range res.Cookies() {
+		audit := domain.CookieAudit{
+			Name:     cookie.Name,
+			Secure:   cookie.Secure,
+			HttpOnly: cookie.HttpOnly,
+			SameSite: sameSiteName(cookie.SameSite),
+		}
+		report.Cookies = append(report.Cookies, audit)
+		if !isHTTPS || !audit.Secure {
+			insecure = append(insecure, audit.Name)
+		} else if !audit.HttpOnly || audit.SameSite == "" {
+			unprotected = append(unprotected, audit.Name)
+		}
+	}
+	switch {
+	case len(report.Cookies) == 0:
+		check(CheckCookieFlags, domain.SecurityCheckPass, "", "No cookies are set")
+	case len(insecure) > 0:
+		check(CheckCookieFlags, domain.SecurityCheckFail, strings.Join(insecure, ", "), "Cookies can be sent over plain HTTP")
+	case len(unprotected) > 0:
+		check(CheckCookieFlags, domain.SecurityCheckWarn, strings.Join(unprotected, ", "), "Cookies lack HttpOnly or SameSite")
+	default:
+		check(CheckCookieFlags, domain.SecurityCheckPass, "", "All cookies are Secure, HttpOnly and SameSite")
+	}
+
+	report.Score = securityScore(report.Checks)
+	report.Grade = securityGrade(report.Score)
+	return report
+}
+
+func tlsInfo(state *tls.ConnectionState, now time.Time) *domain.TLSInfo {
+	info := &domain.TLSInfo{
+		Version:      tls.VersionName(state.Version),
+		CipherSuite:  tls.CipherSuiteName(state.CipherSuite),
+		Certificates: []domain.CertificateInfo{},
+	}
+	for _, cert := range state.PeerCertificates {
+		sans := append([]string{}, cert.DNSNames...)
+		for _, ip := range cert.IPAddresses {
+			sans = append(sans, ip.String())
+		}
+		info.Certificates = append(info.Certificates, domain.CertificateInfo{
+			Subject:       cert.Subject.String(),
+			Issuer:        cert.Issuer.String(),
+			SANs:          sans,
+			NotBefore:     cert.NotBefore,
+			NotAfter:      cert.NotAfter,
+			DaysRemaining: int(math.Floor(cert.NotAfter.Sub(now).Hours() / 24)),
+		})
+	}
+	return info
+}
+
+// hstsMaxAge reads the max-age directive of a Strict-Transport-Security header, 0 when absent
+func hstsMaxAge(header string) int {
+	for _, directive := range strings.Split(header, ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		if strings.EqualFold(strings.TrimSpace(name), "max-age") {
+			maxAge, _ := strconv.Atoi(strings.Trim(strings.TrimSpace(value), `"`))
+			return maxAge
+		}
+	}
+	return 0
+}
+
+func hasCSPDirective(policy, directive string) bool {
+	for _, part := range strings.Split(policy, ";") {
+		fields := strings.Fields(part)
+		if len(fields) > 0 && strings.EqualFold(fields[0], directive) {
+			return true
+		}
+	}
+	return false
+}
+
+// effectiveReferrerPolicy returns the last policy the browser understands; the header may list fallbacks
+func effectiveReferrerPolicy(header string) string {
+	policies := strings.Split(strings.ToLower(header), ",")
+	return strings.TrimSpace(policies[len(policies)-1])
+}
+
+func sameSiteName(mode http.SameSite) string {
+	switch mode {
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteLaxMode:
+		return "Lax"
+	case http.SameSiteNoneMode:
+		return "None"
+	}
+	return ""
+}
+
+// securityScore counts passed checks fully and warnings half
+func securityScore(checks []domain.SecurityCheck) int {
+	points, total := 0.0, 0
+	for _, check := range checks {
+		total += check.Weight
+		switch check.Status {
+		case domain.SecurityCheckPass:
+			points += float64(check.Weight)
+		case domain.SecurityCheckWarn:
+			points += float64(check.Weight) / 2
+		}
+	}
+	return int(math.Round(points * 100 / float64(total)))
+}
+
+// securityRegressions lists the checks of current whose status is worse than in previous
+func securityRegressions(previous *domain.SecurityReport, current domain.SecurityReport) []string {
+	regressions := []string{}
+	if previous == nil {
+		return regressions
+	}
+
+	rank := map[string]int{domain.SecurityCheckFail: 0, domain.SecurityCheckWarn: 1, domain.SecurityCheckPass: 2}
+	before := make(map[string]string, len(previous.Checks))
+	for _, check := range previous.Checks {
+		before[check.Name] = check.Status
+	}
+	for _, check := range current.Checks {
+		if status, ok := before[check.Name]; ok && rank[check.Status] < rank[status] {
+			regressions = append(regressions, check.Name)
+		}
+	}
+	return regressions
+}
+
+func securityGrade(score int) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 70:
+		return "C"
+	case score >= 60:
+		return "D"
+	}
+	return "F"
+}
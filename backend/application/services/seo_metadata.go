@@ -0,0 +1,92 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// SEOMetadata holds the search engine and social sharing metadata declared in a page's <head>
+type SEOMetadata struct {
+	Description  string
+	Keywords     string
+	CanonicalURL string
+	Robots       string // Lowercased directives of <meta name="robots">, e.g. "noindex, follow"
+	Viewport     string
+	Language     string
+	OpenGraph    map[string]string // og:* properties without the "og:" prefix
+	TwitterCard  map[string]string // twitter:* properties without the "twitter:" prefix
+	FaviconURL   string
+}
+
+// extractSEOMetadata reads the SEO metadata of the document, resolving URLs against the page URL
+func extractSEOMetadata(doc *goquery.Document, pageURL *url.URL) SEOMetadata {
+	metadata := SEOMetadata{
+		OpenGraph:   make(map[string]string),
+		TwitterCard: make(map[string]string),
+	}
+
+	metadata.Language = strings.TrimSpace(doc.Find("html").AttrOr("lang", ""))
+
+	doc.Find("meta").Each(func(i int, meta *goquery.Selection) {
+		content := strings.TrimSpace(meta.AttrOr("content", ""))
+		if content == "" {
+			return
+		}
+
+		// Open Graph uses property=, but name= is common in the wild; Twitter cards use either
+		key := strings.ToLower(strings.TrimSpace(meta.AttrOr("property", "")))
+		if key == "" {
+			key = strings.ToLower(strings.TrimSpace(meta.AttrOr("name", "")))
+		}
+
+		switch {
+		case key == "description":
+			setFirst(&metadata.Description, content)
+		case key == "keywords":
+			setFirst(&metadata.Keywords, content)
+		case key == "robots":
+			setFirst(&metadata.Robots, strings.ToLower(content))
+		case key == "viewport":
+			setFirst(&metadata.Viewport, content)
+		case strings.HasPrefix(key, "og:"):
+			setFirstInMap(metadata.OpenGraph, strings.TrimPrefix(key, "og:"), content)
+		case strings.HasPrefix(key, "twitter:"):
+			setFirstInMap(metadata.TwitterCard, strings.TrimPrefix(key, "twitter:"), content)
+		}
+	})
+
+	doc.Find("link[rel][href]").Each(func(i int, link *goquery.Selection) {
+		rels := strings.Fields(strings.ToLower(link.AttrOr("rel", "")))
+		href := strings.TrimSpace(link.AttrOr("href", ""))
+		resolved, err := pageURL.Parse(href)
+		if href == "" || err != nil {
+			return
+		}
+
+		for _, rel := range rels {
+			switch rel {
+			case "canonical":
+				setFirst(&metadata.CanonicalURL, resolved.String())
+			case "icon":
+				setFirst(&metadata.FaviconURL, resolved.String())
+			}
+		}
+	})
+
+	return metadata
+}
+
+// setFirst keeps the first value found for a tag that should only appear once
+func setFirst(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
+
+func setFirstInMap(fields map[string]string, key, value string) {
+	if _, exists := fields[key]; !exists && key != "" {
+		fields[key] = value
+	}
+}
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"backend/application/commands"
+	"backend/domain"
+)
+
+const (
+	defaultSiteCrawlMaxDepth = 2
+	defaultSiteCrawlMaxPages = 50
+	maxSiteCrawlDepth        = 10
+	maxSiteCrawlPages        = 500
+)
+
+// siteCrawl tracks the progress of a running site crawl session
+type siteCrawl struct {
+	host       string
+	options    domain.CrawlOptions
+	include    []*regexp.Regexp
+	exclude    []*regexp.Regexp
+	seen       map[string]bool // Normalized URLs already scheduled
+	pages      int             // Pages scheduled so far, including the root
+	pending    int             // Pages queued or running
+	rootStatus domain.CrawlStatus
+}
+
+// newSiteCrawl validates the site crawl options, applying defaults and limits
+func newSiteCrawl(root *url.URL, options *domain.CrawlOptions) (*siteCrawl, error) {
+	if options.MaxDepth < 0 || options.MaxPages < 0 {
+		return nil, fmt.Errorf("%w: max_depth and max_pages must not be negative", domain.ErrInvalidCrawlOptions)
+	}
+	if options.MaxDepth == 0 {
+		options.MaxDepth = defaultSiteCrawlMaxDepth
+	}
+	if options.MaxPages == 0 {
+		options.MaxPages = defaultSiteCrawlMaxPages
+	}
+	options.MaxDepth = min(options.MaxDepth, maxSiteCrawlDepth)
+	options.MaxPages = min(options.MaxPages, maxSiteCrawlPages)
+
+	include, err := compilePathPatterns(options.IncludePatterns)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := compilePathPatterns(options.ExcludePatterns)
+	if err != nil {
+		return nil, err
+	}
+
+	return &siteCrawl{
+		host:    strings.ToLower(root.Host),
+		options: *options,
+		include: include,
+		exclude: exclude,
+		seen:    map[string]bool{normalizeURL(root): true},
+		pages:   1,
+		pending: 1,
+	}, nil
+}
+
+func compilePathPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid path pattern %q: %v", domain.ErrInvalidCrawlOptions, pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// allows reports whether a link belongs to the site and passes the include/exclude patterns
+func (c *siteCrawl) allows(link *url.URL) bool {
+	if strings.ToLower(link.Host) != c.host {
+		return false
+	}
+
+	linkPath := link.EscapedPath()
+	if linkPath == "" {
+		linkPath = "/"
+	}
+	for _, re := range c.exclude {
+		if re.MatchString(linkPath) {
+			return false
+		}
+	}
+	if len(c.include) == 0 {
+		return true
+	}
+	for _, re := range c.include {
+		if re.MatchString(linkPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeURL returns a canonical form of an http(s) URL used to deduplicate pages:
+// lowercase scheme and host, no default port, no fragment, a cleaned path and sorted query parameters
+func normalizeURL(u *url.URL) string {
+	normalized := *u
+	normalized.Scheme = strings.ToLower(u.Scheme)
+	normalized.Host = strings.ToLower(u.Host)
+	normalized.Fragment = ""
+	normalized.RawFragment = ""
+	normalized.User = nil
+
+	if port := normalized.Port(); (normalized.Scheme == "http" && port == "80") || (normalized.Scheme == "https" && port == "443") {
+		normalized.Host = normalized.Hostname()
+	}
+
+	if normalized.Path == "" {
+		normalized.Path = "/"
+	} else {
+		cleaned := path.Clean(normalized.Path)
+		if strings.HasSuffix(normalized.Path, "/") && cleaned != "/" {
+			cleaned += "/"
+		}
+		normalized.Path = cleaned
+	}
+	normalized.RawPath = ""
+
+	if normalized.RawQuery != "" {
+		normalized.RawQuery = normalized.Query().Encode()
+	}
+
+	return normalized.String()
+}
+
+// startSiteCrawl creates the crawl session for a site crawl request and starts tracking it
+func (s *CrawlService) startSiteCrawl(root *url.URL, result *domain.CrawlResult) (*siteCrawl, error) {
+	state, err := newSiteCrawl(root, &result.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionID, err := s.sessionRepo.Create(domain.CrawlSession{
+		RootURL:  result.URL.String,
+		Status:   domain.CrawlStatusRunning,
+		MaxDepth: result.Options.MaxDepth,
+		MaxPages: result.Options.MaxPages,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save crawl session: %w", err)
+	}
+	result.SessionID = sessionID
+
+	s.mu.Lock()
+	s.sessions[sessionID] = state
+	s.mu.Unlock()
+
+	return state, nil
+}
+
+// followLinks schedules the unseen internal links of a crawled page of a site crawl
+func (s *CrawlService) followLinks(job CrawlJob, result domain.CrawlResult) {
+	s.mu.Lock()
+	state := s.sessions[job.SessionID]
+	s.mu.Unlock()
+	if state == nil {
+		return
+	}
+
+	// A root URL that redirects to another host (http to https, www) moves the site with it
+	if job.Depth == 0 {
+		if finalURL, err := url.Parse(result.FinalURL); err == nil && finalURL.Host != "" {
+			s.mu.Lock()
+			state.host = strings.ToLower(finalURL.Host)
+			state.seen[normalizeURL(finalURL)] = true
+			s.mu.Unlock()
+		}
+	}
+	if job.Depth >= state.options.MaxDepth {
+		return
+	}
+
+	for _, link := range result.InternalLinks {
+		linkURL, err := url.Parse(link)
+		if err != nil {
+			continue
+		}
+
+		s.mu.Lock()
+		normalized := normalizeURL(linkURL)
+		if state.seen[normalized] || state.pages >= state.options.MaxPages || !state.allows(linkURL) {
+			s.mu.Unlock()
+			continue
+		}
+		state.seen[normalized] = true
+		state.pages++
+		state.pending++
+		s.mu.Unlock()
+
+		page := domain.CrawlResult{
+			URL:           domain.NullString{NullString: sql.NullString{String: normalized, Valid: true}},
+			Status:        domain.CrawlStatusQueued,
+			Options:       state.options,
+			SessionID:     job.SessionID,
+			Depth:         job.Depth + 1,
+			HeadingCounts: make(map[string]int),
+		}
+
+		id, err := s.crawlResultRepo.Save(page)
+		if err != nil {
+			fmt.Printf("Error saving queued page %s of crawl session %d: %v\n", normalized, job.SessionID, err)
+			s.releaseSitePage(job.SessionID, page.Depth, domain.CrawlStatusError)
+			continue
+		}
+
+		pageJob := CrawlJob{
+			ID:        id,
+			Cmd:       commands.CrawlCommand{URL: normalized, Options: state.options},
+			SessionID: job.SessionID,
+			Depth:     page.Depth,
+		}
+		if err := s.queue.Enqueue(pageJob); err != nil {
+			page.ID = id
+			failed, _ := failedCrawl(page, err, "%s", err.Error())
+			if updateErr := s.crawlResultRepo.Update(failed); updateErr != nil {
+				fmt.Printf("Error updating rejected crawl result %d: %v\n", id, updateErr)
+			}
+			s.releaseSitePage(job.SessionID, page.Depth, domain.CrawlStatusError)
+		}
+	}
+}
+
+// reopenSitePage counts a re-queued page of a site crawl as pending again and returns the session ID for
+// its job. A page of a session that has already finished is crawled on its own, so 0 is returned.
+func (s *CrawlService) reopenSitePage(sessionID int) int {
+	if sessionID == 0 {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.sessions[sessionID]
+	if state == nil {
+		return 0
+	}
+	state.pending++
+	return sessionID
+}
+
+// releaseSitePage records that a page of a site crawl has finished and completes the session after its last page
+func (s *CrawlService) releaseSitePage(sessionID, depth int, status domain.CrawlStatus) {
+	if sessionID == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	state := s.sessions[sessionID]
+	if state == nil {
+		s.mu.Unlock()
+		return
+	}
+	if depth == 0 {
+		state.rootStatus = status
+	}
+	state.pending--
+	finished := state.pending <= 0
+	if finished {
+		delete(s.sessions, sessionID)
+	}
+	s.mu.Unlock()
+
+	if !finished {
+		return
+	}
+
+	// The session takes the outcome of its root page
+	sessionStatus := domain.CrawlStatusDone
+	if state.rootStatus == domain.CrawlStatusError || state.rootStatus == domain.CrawlStatusCancelled {
+		sessionStatus = state.rootStatus
+	}
+	if err := s.sessionRepo.UpdateStatus(sessionID, sessionStatus); err != nil {
+		fmt.Printf("Error updating status of crawl session %d: %v\n", sessionID, err)
+	}
+}
+
+// GetCrawlSession retrieves a crawl session together with all of its pages
+func (s *CrawlService) GetCrawlSession(id int) (domain.CrawlSession, error) {
+	session, err := s.sessionRepo.GetByID(id)
+	if err != nil {
+		return domain.CrawlSession{}, fmt.Errorf("failed to get crawl session from repository: %w", err)
+	}
+
+	session.Pages, err = s.crawlResultRepo.GetBySessionID(id)
+	if err != nil {
+		return domain.CrawlSession{}, fmt.Errorf("failed to get crawl session pages from repository: %w", err)
+	}
+	return session, nil
+}
@@ -0,0 +1,86 @@
+package services
+
+import "strings"
+
+// srcsetCandidate is an image candidate of a srcset attribute
+type srcsetCandidate struct {
+	URL         string
+	Descriptors []string // Width, density or height descriptors, e.g. 480w or 2x
+}
+
+// parseSrcset splits a srcset attribute into its image candidates following the HTML
+// "parse a srcset attribute" algorithm. A URL runs up to the next whitespace, so commas inside
+// it, e.g. in data: URLs, are kept; a comma only ends a candidate after its descriptors or at
+// the very end of its URL.
+func parseSrcset(srcset string) []srcsetCandidate {
+	var candidates []srcsetCandidate
+	position := 0
+	for {
+		position = len(srcset) - len(strings.TrimLeftFunc(srcset[position:], isSrcsetSeparator))
+		if position == len(srcset) {
+			return candidates
+		}
+
+		urlLength := strings.IndexFunc(srcset[position:], isSrcsetSpace)
+		if urlLength < 0 {
+			urlLength = len(srcset) - position
+		}
+		rawURL := srcset[position : position+urlLength]
+		position += urlLength
+
+		// Trailing commas end the candidate without descriptors
+		if trimmed := strings.TrimRight(rawURL, ","); len(trimmed) < len(rawURL) {
+			if trimmed != "" {
+				candidates = append(candidates, srcsetCandidate{URL: trimmed})
+			}
+			continue
+		}
+
+		var descriptors []string
+		descriptors, position = parseSrcsetDescriptors(srcset, position)
+		candidates = append(candidates, srcsetCandidate{URL: rawURL, Descriptors: descriptors})
+	}
+}
+
+// parseSrcsetDescriptors collects the descriptors that follow a candidate URL up to the comma that
+// ends the candidate, and returns them with the position after that comma. Commas inside
+// parentheses do not end the candidate.
+func parseSrcsetDescriptors(srcset string, position int) ([]string, int) {
+	var descriptors []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			descriptors = append(descriptors, current.String())
+			current.Reset()
+		}
+	}
+
+	inParens := false
+	for ; position < len(srcset); position++ {
+		c := srcset[position]
+		switch {
+		case inParens:
+			current.WriteByte(c)
+			inParens = c != ')'
+		case isSrcsetSpace(rune(c)):
+			flush()
+		case c == ',':
+			flush()
+			return descriptors, position + 1
+		default:
+			current.WriteByte(c)
+			inParens = c == '('
+		}
+	}
+	flush()
+	return descriptors, position
+}
+
+// isSrcsetSpace reports whether c is ASCII whitespace as defined by HTML
+func isSrcsetSpace(c rune) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\f' || c == '\r'
+}
+
+func isSrcsetSeparator(c rune) bool {
+	return c == ',' || isSrcsetSpace(c)
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSrcset(t *testing.T) {
+	tests := []struct {
+		name   string
+		srcset string
+		want   []srcsetCandidate
+	}{
+		{"empty", "", nil},
+		{"only separators", " , ,\n", nil},
+		{"single url", "a.jpg", []srcsetCandidate{{URL: "a.jpg"}}},
+		{"densities", "a.jpg 1x, b.jpg 2x", []srcsetCandidate{
+			{URL: "a.jpg", Descriptors: []string{"1x"}},
+			{URL: "b.jpg", Descriptors: []string{"2x"}},
+		}},
+		{"widths without spaces after commas", "s.jpg 480w,l.jpg 1080w", []srcsetCandidate{
+			{URL: "s.jpg", Descriptors: []string{"480w"}},
+			{URL: "l.jpg", Descriptors: []string{"1080w"}},
+		}},
+		{"several descriptors", "a.jpg 480w 320h", []srcsetCandidate{{URL: "a.jpg", Descriptors: []string{"480w", "320h"}}}},
+		{"comma inside a url", "/img/w_200,h_100/a.jpg 1x, /img/w_400,h_200/a.jpg 2x", []srcsetCandidate{
+			{URL: "/img/w_200,h_100/a.jpg", Descriptors: []string{"1x"}},
+			{URL: "/img/w_400,h_200/a.jpg", Descriptors: []string{"2x"}},
+		}},
+		{"comma at the end of a url", "a.jpg, b.jpg 2x", []srcsetCandidate{
+			{URL: "a.jpg"},
+			{URL: "b.jpg", Descriptors: []string{"2x"}},
+		}},
+		{"several commas at the end of a url", "a.jpg,,, b.jpg", []srcsetCandidate{{URL: "a.jpg"}, {URL: "b.jpg"}}},
+		{"data url", "data:image/png;base64,iVBORw0KGgo= 1x, b.png 2x", []srcsetCandidate{
+			{URL: "data:image/png;base64,iVBORw0KGgo=", Descriptors: []string{"1x"}},
+			{URL: "b.png", Descriptors: []string{"2x"}},
+		}},
+		{"comma inside parentheses", "a.jpg x(1, 2), b.jpg", []srcsetCandidate{
+			{URL: "a.jpg", Descriptors: []string{"x(1, 2)"}},
+			{URL: "b.jpg"},
+		}},
+		{"unclosed parenthesis", "a.jpg x(1, b.jpg", []srcsetCandidate{{URL: "a.jpg", Descriptors: []string{"x(1, b.jpg"}}}},
+		{"surrounding whitespace", "\n\t a.jpg  \t1x  ,\n b.jpg 2x \n", []srcsetCandidate{
+			{URL: "a.jpg", Descriptors: []string{"1x"}},
+			{URL: "b.jpg", Descriptors: []string{"2x"}},
+		}},
+		{"trailing comma", "a.jpg 1x,", []srcsetCandidate{{URL: "a.jpg", Descriptors: []string{"1x"}}}},
+		{"non ascii url", "/bilder/grüße.jpg 1x", []srcsetCandidate{{URL: "/bilder/grüße.jpg", Descriptors: []string{"1x"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseSrcset(tt.srcset); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSrcset(%q) = %+v, want %+v", tt.srcset, got, tt.want)
+			}
+		})
+	}
+}
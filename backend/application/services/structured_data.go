@@ -0,0 +1,241 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"backend/domain"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Formats of structured data found on a page
+const (
+	StructuredDataJSONLD    = "json-ld"
+	StructuredDataMicrodata = "microdata"
+	StructuredDataRDFa      = "rdfa"
+)
+
+// scopeAttributes names the attributes that build an item tree in Microdata or RDFa
+type scopeAttributes struct {
+	scope    string // Starts a new item
+	property string // Names a property of the enclosing item
+	types    string // Space separated types of the item
+}
+
+var (
+	microdataAttributes = scopeAttributes{scope: "itemscope", property: "itemprop", types: "itemtype"}
+	rdfaAttributes      = scopeAttributes{scope: "typeof", property: "property", types: "typeof"}
+)
+
+// extractStructuredData parses the JSON-LD, Microdata and RDFa entities of the document.
+// Blocks that cannot be parsed are reported as issues instead of being dropped.
+func extractStructuredData(doc *goquery.Document, pageURL *url.URL) ([]domain.StructuredDataEntity, []domain.StructuredDataIssue) {
+	entities, issues := extractJSONLD(doc)
+	entities = append(entities, extractScopedItems(doc, pageURL, StructuredDataMicrodata, microdataAttributes)...)
+	entities = append(entities, extractScopedItems(doc, pageURL, StructuredDataRDFa, rdfaAttributes)...)
+	return entities, issues
+}
+
+func extractJSONLD(doc *goquery.Document) ([]domain.StructuredDataEntity, []domain.StructuredDataIssue) {
+	var entities []domain.StructuredDataEntity
+	var issues []domain.StructuredDataIssue
+
+	doc.Find(`script[type="application/ld+json" i]`).Each(func(i int, script *goquery.Selection) {
+		block := i + 1
+		content := strings.TrimSpace(script.Text())
+		if content == "" {
+			issues = append(issues, jsonLDIssue("block %d is empty", block))
+			return
+		}
+
+		var data any
+		if err := json.Unmarshal([]byte(content), &data); err != nil {
+			issues = append(issues, jsonLDIssue("block %d is not valid JSON: %v", block, err))
+			return
+		}
+
+		// A block holds one entity, an array of entities or an @graph of entities
+		var nodes []any
+		switch value := data.(type) {
+		case []any:
+			nodes = value
+		case map[string]any:
+			if graph, ok := value["@graph"].([]any); ok {
+				nodes = graph
+			} else {
+				nodes = []any{value}
+			}
+		default:
+			issues = append(issues, jsonLDIssue("block %d must contain an object or an array", block))
+			return
+		}
+
+		for _, node := range nodes {
+			object, ok := node.(map[string]any)
+			if !ok {
+				issues = append(issues, jsonLDIssue("block %d contains an entity that is not an object", block))
+				continue
+			}
+
+			types := jsonLDTypes(object["@type"])
+			if len(types) == 0 {
+				issues = append(issues, jsonLDIssue("block %d contains an entity without @type", block))
+				continue
+			}
+
+			properties := make(map[string]any, len(object))
+			for key, value := range object {
+				if key != "@context" && key != "@type" {
+					properties[key] = value
+				}
+			}
+			entities = append(entities, domain.StructuredDataEntity{
+				Format:     StructuredDataJSONLD,
+				Types:      types,
+				Properties: properties,
+			})
+		}
+	})
+
+	return entities, issues
+}
+
+func jsonLDIssue(format string, args ...any) domain.StructuredDataIssue {
+	return domain.StructuredDataIssue{Format: StructuredDataJSONLD, Message: fmt.Sprintf(format, args...)}
+}
+
+// jsonLDTypes reads @type, which is either a string or an array of strings
+func jsonLDTypes(value any) []string {
+	var types []string
+	switch typed := value.(type) {
+	case string:
+		types = append(types, normalizeSchemaType(typed))
+	case []any:
+		for _, item := range typed {
+			if name, ok := item.(string); ok {
+				types = append(types, normalizeSchemaType(name))
+			}
+		}
+	}
+	return types
+}
+
+// normalizeSchemaType shortens schema.org type URLs and prefixes to the bare type name
+func normalizeSchemaType(name string) string {
+	for _, prefix := range []string{"https://schema.org/", "http://schema.org/", "schema:"} {
+		if strings.HasPrefix(name, prefix) {
+			return strings.TrimPrefix(name, prefix)
+		}
+	}
+	return name
+}
+
+// extractScopedItems returns the top-level items of a Microdata or RDFa tree, i.e. items that are not a property of another item
+func extractScopedItems(doc *goquery.Document, pageURL *url.URL, format string, attrs scopeAttributes) []domain.StructuredDataEntity {
+	var entities []domain.StructuredDataEntity
+	doc.Find("[" + attrs.scope + "]").Each(func(i int, item *goquery.Selection) {
+		if _, isProperty := item.Attr(attrs.property); isProperty {
+			return
+		}
+		entities = append(entities, domain.StructuredDataEntity{
+			Format:     format,
+			Types:      scopedItemTypes(item, attrs),
+			Properties: scopedItemProperties(item, pageURL, attrs),
+		})
+	})
+	return entities
+}
+
+func scopedItemTypes(item *goquery.Selection, attrs scopeAttributes) []string {
+	types := []string{}
+	for _, name := range strings.Fields(item.AttrOr(attrs.types, "")) {
+		types = append(types, normalizeSchemaType(name))
+	}
+	return types
+}
+
+// scopedItemProperties collects the properties of an item, descending until another item starts
+func scopedItemProperties(item *goquery.Selection, pageURL *url.URL, attrs scopeAttributes) map[string]any {
+	properties := make(map[string]any)
+
+	var walk func(*goquery.Selection)
+	walk = func(parent *goquery.Selection) {
+		parent.Children().Each(func(i int, child *goquery.Selection) {
+			_, startsItem := child.Attr(attrs.scope)
+			if names, isProperty := child.Attr(attrs.property); isProperty {
+				var value any
+				if startsItem {
+					nested := scopedItemProperties(child, pageURL, attrs)
+					nested["@type"] = scopedItemTypes(child, attrs)
+					value = nested
+				} else {
+					value = scopedPropertyValue(child, pageURL)
+				}
+				for _, name := range strings.Fields(names) {
+					addProperty(properties, normalizeSchemaType(name), value)
+				}
+			}
+			if !startsItem {
+				walk(child)
+			}
+		})
+	}
+	walk(item)
+
+	return properties
+}
+
+// scopedPropertyValue reads a property value the way the Microdata spec defines it per element
+func scopedPropertyValue(element *goquery.Selection, pageURL *url.URL) any {
+	if content, exists := element.Attr("content"); exists {
+		return content
+	}
+
+	var urlAttr string
+	switch goquery.NodeName(element) {
+	case "a", "area", "link":
+		urlAttr = "href"
+	case "audio", "embed", "iframe", "img", "source", "track", "video":
+		urlAttr = "src"
+	case "object":
+		urlAttr = "data"
+	case "data", "meter":
+		return element.AttrOr("value", "")
+	case "time":
+		if datetime, exists := element.Attr("datetime"); exists {
+			return datetime
+		}
+	}
+	if urlAttr == "" {
+		if _, exists := element.Attr("resource"); exists {
+			urlAttr = "resource"
+		}
+	}
+	if urlAttr != "" {
+		if value, exists := element.Attr(urlAttr); exists {
+			if resolved, err := pageURL.Parse(strings.TrimSpace(value)); err == nil {
+				return resolved.String()
+			}
+			return value
+		}
+	}
+
+	return strings.Join(strings.Fields(element.Text()), " ")
+}
+
+// addProperty stores a property value, turning repeated properties into a list
+func addProperty(properties map[string]any, name string, value any) {
+	existing, exists := properties[name]
+	if !exists {
+		properties[name] = value
+		return
+	}
+	if values, ok := existing.([]any); ok {
+		properties[name] = append(values, value)
+		return
+	}
+	properties[name] = []any{existing, value}
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// maxVisibleTextBytes caps the indexed text of a page so huge documents do not bloat the search index
+const maxVisibleTextBytes = 64 * 1024
+
+// invisibleElements hold content that is not rendered as text
+var invisibleElements = map[string]bool{
+	"head": true, "script": true, "style": true, "noscript": true, "template": true,
+	"svg": true, "math": true, "iframe": true, "object": true, "canvas": true,
+}
+
+// blockElements separate their text from the surrounding text when rendered
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "br": true, "dd": true, "div": true,
+	"dl": true, "dt": true, "figcaption": true, "figure": true, "footer": true, "form": true, "h1": true,
+	"h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "header": true, "hr": true, "li": true,
+	"main": true, "nav": true, "ol": true, "p": true, "pre": true, "section": true, "table": true,
+	"td": true, "th": true, "tr": true, "ul": true,
+}
+
+// extractVisibleText returns the text of the body a visitor would see, with whitespace collapsed
+func extractVisibleText(doc *goquery.Document) string {
+	var text strings.Builder
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		if text.Len() >= maxVisibleTextBytes {
+			return
+		}
+		switch node.Type {
+		case html.TextNode:
+			text.WriteString(node.Data)
+			return
+		case html.ElementNode:
+			if invisibleElements[node.Data] || hasAttr(node, "hidden") {
+				return
+			}
+			if blockElements[node.Data] {
+				text.WriteByte(' ')
+			}
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+		if node.Type == html.ElementNode && blockElements[node.Data] {
+			text.WriteByte(' ')
+		}
+	}
+	for _, node := range doc.Find("body").Nodes {
+		walk(node)
+	}
+
+	visible := strings.Join(strings.Fields(text.String()), " ")
+	if len(visible) > maxVisibleTextBytes {
+		visible = visible[:maxVisibleTextBytes]
+		for !utf8.ValidString(visible) {
+			visible = visible[:len(visible)-1]
+		}
+	}
+	return visible
+}
+
+func hasAttr(node *html.Node, key string) bool {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
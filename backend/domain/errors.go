@@ -3,10 +3,29 @@ package domain
 import "errors"
 
 var (
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrInvalidURLFormat   = errors.New("invalid URL format")
-	ErrURLFetchFailed     = errors.New("failed to fetch URL")
-	ErrHTMLParseFailed    = errors.New("failed to parse HTML")
-	ErrTokenInvalid       = errors.New("invalid or expired token")
-	ErrMissingAuthHeader  = errors.New("missing or invalid Authorization header")
+	ErrInvalidCredentials     = errors.New("invalid credentials")
+	ErrInvalidURLFormat       = errors.New("invalid URL format")
+	ErrURLFetchFailed         = errors.New("failed to fetch URL")
+	ErrDisallowedByRobots     = errors.New("URL is disallowed by robots.txt")
+	ErrRedirectLoop           = errors.New("redirect loop detected")
+	ErrTooManyRedirects       = errors.New("too many redirects")
+	ErrResponseTooLarge       = errors.New("response body exceeds the size limit")
+	ErrHTMLParseFailed        = errors.New("failed to parse HTML")
+	ErrTokenInvalid           = errors.New("invalid or expired token")
+	ErrMissingAuthHeader      = errors.New("missing or invalid Authorization header")
+	ErrCrawlResultNotFound    = errors.New("crawl result not found")
+	ErrCrawlQueueFull         = errors.New("crawl queue is full, try again later")
+	ErrInvalidCrawlOptions    = errors.New("invalid crawl options")
+	ErrInvalidSorting         = errors.New("invalid sorting")
+	ErrInvalidFilter          = errors.New("invalid filter")
+	ErrInvalidCursor          = errors.New("invalid cursor")
+	ErrCrawlSessionNotFound   = errors.New("crawl session not found")
+	ErrSecurityReportNotFound = errors.New("security report not found")
+	ErrUnknownAnalyzer        = errors.New("unknown analyzer")
+	ErrAnalyzerFailed         = errors.New("page analysis failed")
+	ErrCrawlCancelled         = errors.New("crawl was cancelled")
+	ErrCrawlInterrupted       = errors.New("crawl was interrupted by a server restart")
+	ErrNoCancellableCrawls    = errors.New("no queued or running crawl jobs found for the provided IDs")
+	ErrNoRequeueableCrawls    = errors.New("no finished crawl jobs found for the provided IDs")
+	ErrCrawlQueueClosed       = errors.New("crawl queue is closed")
 )
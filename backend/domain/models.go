@@ -36,19 +36,386 @@ func (ns *NullString) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// CrawlStatus represents the lifecycle state of a crawl job
+type CrawlStatus string
+
+const (
+	CrawlStatusQueued    CrawlStatus = "queued"
+	CrawlStatusRunning   CrawlStatus = "running"
+	CrawlStatusDone      CrawlStatus = "done"
+	CrawlStatusError     CrawlStatus = "error"
+	CrawlStatusCancelled CrawlStatus = "cancelled"
+)
+
+// IsFinished reports whether the crawl job has stopped and can be re-queued
+func (s CrawlStatus) IsFinished() bool {
+	return s == CrawlStatusDone || s == CrawlStatusError || s == CrawlStatusCancelled
+}
+
+// CrawlOptions holds the per-request settings of a crawl, kept so the crawl can be re-run the same way
+type CrawlOptions struct {
+	Analyzers         []string     `json:"analyzers,omitempty"`          // Only run these analyzers; empty means all
+	DisabledAnalyzers []string     `json:"disabled_analyzers,omitempty"` // Skip these analyzers
+	IgnoreRobots      bool         `json:"ignore_robots,omitempty"`      // Skip robots.txt rules and Crawl-delay, for sites we own
+	Fetch             FetchOptions `json:"fetch,omitempty"`              // Overrides of the global HTTP client settings
+
+	// Site crawl mode follows internal links and stores every page under one CrawlSession
+	SiteCrawl       bool     `json:"site_crawl,omitempty"`
+	MaxDepth        int      `json:"max_depth,omitempty"`
+	MaxPages        int      `json:"max_pages,omitempty"`
+	IncludePatterns []string `json:"include_patterns,omitempty"` // Regular expressions a link path must match one of
+	ExcludePatterns []string `json:"exclude_patterns,omitempty"` // Regular expressions a link path must not match
+}
+
+// FetchOptions configures the HTTP client used to fetch pages. Zero values fall back to the global defaults.
+type FetchOptions struct {
+	ConnectTimeoutMs int               `json:"connect_timeout_ms,omitempty"`
+	ReadTimeoutMs    int               `json:"read_timeout_ms,omitempty"`
+	MaxBodyBytes     int64             `json:"max_body_bytes,omitempty"`
+	MaxRedirects     int               `json:"max_redirects,omitempty"` // Negative disables following redirects
+	Headers          map[string]string `json:"headers,omitempty"`
+	UserAgent        string            `json:"user_agent,omitempty"`
+	ProxyURL         string            `json:"proxy_url,omitempty"` // http, https or socks5 proxy
+}
+
 // CrawlResult holds the data extracted from the crawled URL
 type CrawlResult struct {
-	ID                  int               `json:"id"` // Added ID field
-	HTMLVersion         string            `json:"html_version"`
-	URL                 NullString        `json:"url"`
-	PageTitle           string            `json:"page_title"`
-	HeadingCounts       map[string]int    `json:"heading_counts"`
-	InternalLinkCount   int               `json:"internal_link_count"`
-	ExternalLinkCount   int               `json:"external_link_count"`
-	InaccessibleLinkCount int             `json:"inaccessible_link_count"` // Only for the main URL in this implementation
-	HasLoginForm        bool              `json:"has_login_form"`
-	Error               string            `json:"error"`
-	CreatedAt           time.Time         `json:"created_at"` // Added CreatedAt field
+	ID                           int                    `json:"id"` // Added ID field
+	Status                       CrawlStatus            `json:"status"`
+	Options                      CrawlOptions           `json:"options"`
+	SessionID                    int                    `json:"session_id,omitempty"` // Parent CrawlSession in site crawl mode
+	Depth                        int                    `json:"depth"`                // Link distance from the session's root URL
+	HTMLVersion                  string                 `json:"html_version"`
+	HasDoctype                   bool                   `json:"has_doctype"`
+	DocumentMode                 string                 `json:"document_mode"` // no-quirks, limited-quirks or quirks
+	Charset                      string                 `json:"charset"`       // Encoding the page was decoded from, e.g. utf-8 or shift_jis
+	URL                          NullString             `json:"url"`
+	FinalURL                     string                 `json:"final_url"`        // URL of the page after following redirects
+	RedirectChain                []RedirectHop          `json:"redirect_chain"`   // Redirects followed from URL to FinalURL
+	HTTPStatusCode               int                    `json:"http_status_code"` // Status code of the final response
+	HTTPProtocol                 string                 `json:"http_protocol"`    // e.g. HTTP/1.1 or HTTP/2.0
+	ContentType                  string                 `json:"content_type"`
+	ContentLength                int64                  `json:"content_length"` // Content-Length header, -1 when absent
+	TransferSize                 int64                  `json:"transfer_size"`  // Body bytes received, compressed if the server compressed them
+	BodySize                     int64                  `json:"body_size"`      // Body bytes after decompression
+	DNSLookupMs                  int64                  `json:"dns_lookup_ms"`
+	ConnectMs                    int64                  `json:"connect_ms"`
+	TLSHandshakeMs               int64                  `json:"tls_handshake_ms"`
+	TTFBMs                       int64                  `json:"ttfb_ms"`       // Time to first byte of the final response
+	DownloadMs                   int64                  `json:"download_ms"`   // From the first byte until the body was read
+	TotalTimeMs                  int64                  `json:"total_time_ms"` // Whole fetch including redirects
+	PageTitle                    string                 `json:"page_title"`
+	MetaDescription              string                 `json:"meta_description"`
+	MetaKeywords                 string                 `json:"meta_keywords"`
+	CanonicalURL                 string                 `json:"canonical_url"`
+	MetaRobots                   string                 `json:"meta_robots"` // Directives of <meta name="robots">, e.g. "noindex, follow"
+	Viewport                     string                 `json:"viewport"`
+	Language                     string                 `json:"language"`     // lang attribute of the <html> element
+	OpenGraph                    map[string]string      `json:"open_graph"`   // og:* properties keyed without the prefix
+	TwitterCard                  map[string]string      `json:"twitter_card"` // twitter:* properties keyed without the prefix
+	FaviconURL                   string                 `json:"favicon_url"`
+	HeadingCounts                map[string]int         `json:"heading_counts"`
+	MultipleH1                   bool                   `json:"multiple_h1"`
+	MissingH1                    bool                   `json:"missing_h1"`
+	HeadingLevelSkips            int                    `json:"heading_level_skips"` // Headings more than one level deeper than the previous heading
+	InternalLinkCount            int                    `json:"internal_link_count"`
+	ExternalLinkCount            int                    `json:"external_link_count"`
+	InaccessibleLinkCount        int                    `json:"inaccessible_link_count"` // Only for the main URL in this implementation
+	ImageCount                   int                    `json:"image_count"`
+	ImagesMissingAltCount        int                    `json:"images_missing_alt_count"`
+	ImagesEmptyAltCount          int                    `json:"images_empty_alt_count"`
+	ImagesMissingDimensionsCount int                    `json:"images_missing_dimensions_count"` // Without width or height
+	BrokenImageCount             int                    `json:"broken_image_count"`
+	AccessibilityErrorCount      int                    `json:"accessibility_error_count"`
+	AccessibilityWarningCount    int                    `json:"accessibility_warning_count"`
+	ActiveMixedContentCount      int                    `json:"active_mixed_content_count"`  // Scripts, stylesheets, frames and forms over plain HTTP on an HTTPS page
+	PassiveMixedContentCount     int                    `json:"passive_mixed_content_count"` // Images and media over plain HTTP on an HTTPS page
+	SecurityScore                *int                   `json:"security_score"`              // 0..100, nil when the security analyzer did not run
+	SecurityGrade                string                 `json:"security_grade"`              // A..F
+	HasLoginForm                 bool                   `json:"has_login_form"`
+	LoginFormConfidence          float64                `json:"login_form_confidence"` // 0..1 score of the most login-like form
+	LoginFormAction              string                 `json:"login_form_action"`     // Resolved action URL of that form
+	Error                        string                 `json:"error"`
+	BrokenLinks                  []BrokenLink           `json:"broken_links,omitempty"`
+	StructuredData               []StructuredDataEntity `json:"structured_data,omitempty"`
+	StructuredDataIssues         []StructuredDataIssue  `json:"structured_data_issues,omitempty"`
+	InternalLinks                []string               `json:"-"` // Normalized internal http(s) links, followed in site crawl mode
+	Links                        []Link                 `json:"-"` // Every link of the page, stored in the link inventory
+	Images                       []Image                `json:"-"` // Every image source of the page, stored in the image inventory
+	AccessibilityIssues          []AccessibilityIssue   `json:"-"`
+	Headings                     []Heading              `json:"-"` // Heading outline in document order
+	SecurityReport               *SecurityReport        `json:"-"`
+	VisibleText                  string                 `json:"-"`                      // Text a visitor sees on the page, indexed for full-text search
+	SearchScore                  float64                `json:"search_score,omitempty"` // Relevance of a full-text search hit
+	Snippet                      string                 `json:"snippet,omitempty"`      // HTML-escaped excerpt of a search hit with the matches in <mark>
+	MixedContent                 []MixedContent         `json:"-"`
+	CreatedAt                    time.Time              `json:"created_at"` // Added CreatedAt field
+}
+
+// RedirectHop is a single redirect response followed while fetching a crawled URL
+type RedirectHop struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+	Location   string `json:"location"` // Location header as sent by the server
+	LatencyMs  int64  `json:"latency_ms"`
+}
+
+// CrawlResultFilter narrows down the crawl results returned by list queries. Nil fields do not filter.
+type CrawlResultFilter struct {
+	HasMetaDescription *bool
+	HasCanonical       *bool
+	NoIndex            *bool  // Meta robots contains noindex or none
+	Language           string // Matches the language and its regional variants, e.g. "en" matches "en-US"
+	HasOpenGraph       *bool
+	HasTwitterCard     *bool
+	HTTPStatusCode     int // 0 does not filter
+	HTTPProtocol       string
+	MinTTFBMs          *int64
+	MaxTTFBMs          *int64
+	MinTotalTimeMs     *int64
+	MaxTotalTimeMs     *int64
+	Expression         *FilterExpression // Typed conditions and their boolean combinations
+	Search             string            // Full-text query; restricts the results to its matches and sets their SearchScore
+}
+
+// Operators of filter expressions
+const (
+	FilterAnd      = "and"
+	FilterOr       = "or"
+	FilterNot      = "not"
+	FilterEq       = "="
+	FilterNe       = "!="
+	FilterLt       = "<"
+	FilterLte      = "<="
+	FilterGt       = ">"
+	FilterGte      = ">="
+	FilterContains = "~" // Substring match on text fields
+)
+
+// FilterExpression is a node of a boolean filter: a comparison of a field with a value,
+// or an and/or/not combination of its operands
+type FilterExpression struct {
+	Op       string
+	Field    string             // Comparisons only
+	Value    string             // Comparisons only; typed according to the field
+	Operands []FilterExpression // and, or and not only
+}
+
+// Sort directions
+const (
+	SortAscending  = "asc"
+	SortDescending = "desc"
+)
+
+// SortField is one entry of an ordered sort specification, e.g. {"field": "page_title", "direction": "asc"}
+type SortField struct {
+	Field     string `json:"field"`
+	Direction string `json:"direction"` // asc or desc
+}
+
+// SearchDocument is the text of a crawled page as indexed for full-text search
+type SearchDocument struct {
+	CrawlResultID   int
+	Title           string
+	MetaDescription string
+	Headings        string // Heading texts in document order, one per line
+	Body            string // Visible text of the page
+}
+
+// Total count modes of list queries
+const (
+	CountExact     = "exact"
+	CountEstimated = "estimated" // Cheaper, but may be approximate
+	CountNone      = "none"
+)
+
+// CrawlSession groups the pages found by a site crawl under its root URL
+type CrawlSession struct {
+	ID        int           `json:"id"`
+	RootURL   string        `json:"root_url"`
+	Status    CrawlStatus   `json:"status"`
+	MaxDepth  int           `json:"max_depth"`
+	MaxPages  int           `json:"max_pages"`
+	PageCount int           `json:"page_count"`
+	CreatedAt time.Time     `json:"created_at"`
+	Pages     []CrawlResult `json:"pages,omitempty"`
+}
+
+// BrokenLink holds a link found on a crawled page that could not be reached
+type BrokenLink struct {
+	ID            int    `json:"id"`
+	CrawlResultID int    `json:"crawl_result_id"`
+	URL           string `json:"url"`
+	StatusCode    int    `json:"status_code"` // 0 when the request failed before a response was received
+	Error         string `json:"error"`
+}
+
+// StructuredDataEntity is a schema.org entity declared on a crawled page
+type StructuredDataEntity struct {
+	ID            int            `json:"id"`
+	CrawlResultID int            `json:"crawl_result_id"`
+	Format        string         `json:"format"` // json-ld, microdata or rdfa
+	Types         []string       `json:"types"`  // e.g. Product or Article
+	Properties    map[string]any `json:"properties"`
+}
+
+// StructuredDataIssue reports structured data on a crawled page that could not be parsed
+type StructuredDataIssue struct {
+	ID            int    `json:"id"`
+	CrawlResultID int    `json:"crawl_result_id"`
+	Format        string `json:"format"`
+	Message       string `json:"message"`
+}
+
+// Link schemes of the link inventory
+const (
+	LinkSchemeHTTP       = "http"
+	LinkSchemeHTTPS      = "https"
+	LinkSchemeMailto     = "mailto"
+	LinkSchemeTel        = "tel"
+	LinkSchemeJavaScript = "javascript"
+	LinkSchemeFragment   = "fragment" // href only consists of a #fragment
+	LinkSchemeOther      = "other"
+)
+
+// Link classifications of the link inventory
+const (
+	LinkInternal  = "internal"
+	LinkExternal  = "external"
+	LinkSubdomain = "subdomain" // Another host of the same registrable domain
+)
+
+// Link is an <a href> found on a crawled page
+type Link struct {
+	ID             int    `json:"id"`
+	CrawlResultID  int    `json:"crawl_result_id"`
+	URL            string `json:"url"` // Resolved against the page URL
+	Href           string `json:"href"`
+	AnchorText     string `json:"anchor_text"`
+	Rel            string `json:"rel"` // Lowercased rel tokens, e.g. "nofollow noopener"
+	Target         string `json:"target"`
+	Scheme         string `json:"scheme"`
+	Classification string `json:"classification"` // Empty for mailto, tel, javascript and other schemes
+}
+
+// LinkFilter narrows down the links of a crawl result. Empty fields do not filter.
+type LinkFilter struct {
+	Classification string
+	Scheme         string
+	Rel            string // A single rel token, e.g. nofollow
+	Query          string // Matches the URL or anchor text
+}
+
+// Image is an image source found on a crawled page: an <img> src, a <picture> <source> or a srcset candidate
+type Image struct {
+	ID            int    `json:"id"`
+	CrawlResultID int    `json:"crawl_result_id"`
+	URL           string `json:"url"`
+	Element       string `json:"element"`    // img or source
+	Attribute     string `json:"attribute"`  // src or srcset
+	Descriptor    string `json:"descriptor"` // srcset width or density descriptor, e.g. 480w or 2x
+	HasAlt        bool   `json:"has_alt"`
+	Alt           string `json:"alt"`
+	Width         string `json:"width"`
+	Height        string `json:"height"`
+	StatusCode    int    `json:"status_code"` // 0 when the image was not or could not be requested
+	ByteSize      int64  `json:"byte_size"`   // -1 when unknown
+	Broken        bool   `json:"broken"`
+	Error         string `json:"error"`
+}
+
+// Heading is an entry of the heading outline of a crawled page
+type Heading struct {
+	ID            int    `json:"id"`
+	CrawlResultID int    `json:"crawl_result_id"`
+	Level         int    `json:"level"` // 1 for h1 through 6 for h6
+	Text          string `json:"text"`
+	Position      int    `json:"position"` // 1-based position among the headings of the page
+}
+
+// Severities of accessibility issues
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// AccessibilityIssue is a finding of a static accessibility rule on a crawled page
+type AccessibilityIssue struct {
+	ID            int    `json:"id"`
+	CrawlResultID int    `json:"crawl_result_id"`
+	RuleID        string `json:"rule_id"`
+	Severity      string `json:"severity"`
+	Message       string `json:"message"`
+	Selector      string `json:"selector"` // CSS selector of the offending element
+}
+
+// Types of mixed content
+const (
+	MixedContentActive  = "active"  // Can alter the page, e.g. scripts; blocked by browsers
+	MixedContentPassive = "passive" // Only displayed, e.g. images; loaded with a warning or upgraded
+)
+
+// MixedContent is a subresource or form target of an HTTPS page that uses plain HTTP
+type MixedContent struct {
+	ID            int    `json:"id"`
+	CrawlResultID int    `json:"crawl_result_id"`
+	URL           string `json:"url"`
+	Element       string `json:"element"`   // e.g. script, img or form
+	Attribute     string `json:"attribute"` // e.g. src, srcset, href or action
+	Type          string `json:"type"`      // active or passive
+}
+
+// Statuses of security checks
+const (
+	SecurityCheckPass = "pass"
+	SecurityCheckWarn = "warn"
+	SecurityCheckFail = "fail"
+)
+
+// SecurityReport is the scored security header and TLS audit of a crawled page
+type SecurityReport struct {
+	ID            int             `json:"id"`
+	CrawlResultID int             `json:"crawl_result_id"`
+	Score         int             `json:"score"` // 0..100, the weighted share of passed checks
+	Grade         string          `json:"grade"` // A..F
+	TLS           *TLSInfo        `json:"tls"`   // nil when the page was not served over HTTPS
+	Checks        []SecurityCheck `json:"checks"`
+	Cookies       []CookieAudit   `json:"cookies"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// TLSInfo describes the TLS connection the final response was received on
+type TLSInfo struct {
+	Version      string            `json:"version"` // e.g. TLS 1.3
+	CipherSuite  string            `json:"cipher_suite"`
+	Certificates []CertificateInfo `json:"certificates"` // Chain as sent by the server, leaf first
+}
+
+// CertificateInfo describes a certificate of the TLS chain
+type CertificateInfo struct {
+	Subject       string    `json:"subject"`
+	Issuer        string    `json:"issuer"`
+	SANs          []string  `json:"sans"` // DNS names and IP addresses
+	NotBefore     time.Time `json:"not_before"`
+	NotAfter      time.Time `json:"not_after"`
+	DaysRemaining int       `json:"days_remaining"` // At crawl time; negative once expired
+}
+
+// SecurityCheck is the outcome of a single check of a security report
+type SecurityCheck struct {
+	Name    string `json:"name"` // e.g. strict-transport-security
+	Status  string `json:"status"`
+	Weight  int    `json:"weight"` // Points the check contributes to the score when it passes
+	Value   string `json:"value"`  // Header value as received
+	Message string `json:"message"`
+}
+
+// CookieAudit lists the security attributes of a cookie set by the page
+type CookieAudit struct {
+	Name     string `json:"name"`
+	Secure   bool   `json:"secure"`
+	HttpOnly bool   `json:"http_only"`
+	SameSite string `json:"same_site"` // Strict, Lax, None or empty when not set
 }
 
 // LoginRequest defines the structure for the login POST request body
@@ -59,7 +426,16 @@ type LoginRequest struct {
 
 // CrawlRequest defines the structure for the POST request body
 type CrawlRequest struct {
-	URL string `json:"url" form:"url" binding:"required"`
+	URL               string       `json:"url" form:"url" binding:"required"`
+	Analyzers         []string     `json:"analyzers"`
+	DisabledAnalyzers []string     `json:"disabled_analyzers"`
+	IgnoreRobots      bool         `json:"ignore_robots"`
+	Fetch             FetchOptions `json:"fetch"`
+	SiteCrawl         bool         `json:"site_crawl"`
+	MaxDepth          int          `json:"max_depth"`
+	MaxPages          int          `json:"max_pages"`
+	IncludePatterns   []string     `json:"include_patterns"`
+	ExcludePatterns   []string     `json:"exclude_patterns"`
 }
 
 // Claims defines the structure of the JWT claims
@@ -72,3 +448,13 @@ type Claims struct {
 type DeleteCrawlResultsRequest struct {
 	IDs []int `json:"ids" binding:"required"`
 }
+
+// CancelCrawlsRequest defines the structure for the batch cancel POST request body
+type CancelCrawlsRequest struct {
+	IDs []int `json:"ids" binding:"required"`
+}
+
+// RequeueCrawlsRequest defines the structure for the batch re-run POST request body
+type RequeueCrawlsRequest struct {
+	IDs []int `json:"ids" binding:"required"`
+}
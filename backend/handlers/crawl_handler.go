@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -26,7 +28,7 @@ func NewCrawlHandler(crawlService *services.CrawlService) *CrawlHandler {
 	}
 }
 
-// Crawl handles the URL crawling request
+// Crawl handles the URL crawling request by queueing it for the worker pool
 func (h *CrawlHandler) Crawl(c *gin.Context) {
 	var req domain.CrawlRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -36,28 +38,261 @@ func (h *CrawlHandler) Crawl(c *gin.Context) {
 
 	cmd := commands.CrawlCommand{
 		URL: req.URL,
+		Options: domain.CrawlOptions{
+			Analyzers:         req.Analyzers,
+			DisabledAnalyzers: req.DisabledAnalyzers,
+			IgnoreRobots:      req.IgnoreRobots,
+			Fetch:             req.Fetch,
+			SiteCrawl:         req.SiteCrawl,
+			MaxDepth:          req.MaxDepth,
+			MaxPages:          req.MaxPages,
+			IncludePatterns:   req.IncludePatterns,
+			ExcludePatterns:   req.ExcludePatterns,
+		},
 	}
 
-	result, id, err := h.crawlService.Crawl(cmd)
+	result, err := h.crawlService.Enqueue(cmd)
 	if err != nil {
-		fmt.Println(">>>>> ", result.Error)
-		switch err {
-			case domain.ErrInvalidURLFormat:
-				c.JSON(http.StatusBadRequest, gin.H{"message": result.Error})
-				return
-			case domain.ErrURLFetchFailed, domain.ErrHTMLParseFailed:
-				c.JSON(http.StatusInternalServerError, gin.H{"message": result.Error})
-				return
+		switch {
+		case errors.Is(err, domain.ErrInvalidURLFormat), errors.Is(err, domain.ErrUnknownAnalyzer), errors.Is(err, domain.ErrInvalidCrawlOptions):
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		case errors.Is(err, domain.ErrCrawlQueueFull), errors.Is(err, domain.ErrCrawlQueueClosed):
+			c.JSON(http.StatusServiceUnavailable, gin.H{"message": err.Error()})
+			return
 		}
 
 		// Generic error for unexpected issues
+		fmt.Println("Failed to queue crawl request:", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to process crawl request"})
-		
 		return
 	}
 
-	// fmt.Println(result)
-	c.JSON(http.StatusOK, gin.H{"message": "Crawl the url successfully", "id": id})
+	response := gin.H{"message": "Crawl job queued successfully", "id": result.ID, "status": result.Status}
+	if result.SessionID != 0 {
+		response["session_id"] = result.SessionID
+	}
+	c.JSON(http.StatusAccepted, response)
+}
+
+// GetAnalyzers handles the request to list the analyzers available to crawl requests
+func (h *CrawlHandler) GetAnalyzers(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"list": h.crawlService.GetAnalyzers()})
+}
+
+// GetCrawlResult handles the request to get a single crawl result, e.g. to poll its status
+func (h *CrawlHandler) GetCrawlResult(c *gin.Context) {
+	id, ok := parseCrawlResultID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.crawlService.GetCrawlResult(id)
+	if err != nil {
+		respondCrawlResultError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetBrokenLinks handles the request to list the broken links of a crawl result
+func (h *CrawlHandler) GetBrokenLinks(c *gin.Context) {
+	id, ok := parseCrawlResultID(c)
+	if !ok {
+		return
+	}
+
+	links, err := h.crawlService.GetBrokenLinks(id)
+	if err != nil {
+		respondCrawlResultError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"list": links, "total_count": len(links)})
+}
+
+// GetLinks handles the request to page through the link inventory of a crawl result
+func (h *CrawlHandler) GetLinks(c *gin.Context) {
+	id, ok := parseCrawlResultID(c)
+	if !ok {
+		return
+	}
+
+	currPage, err := strconv.Atoi(c.DefaultQuery("currPage", "1"))
+	if err != nil || currPage < 1 {
+		currPage = 1
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10
+	}
+
+	query := queries.GetCrawlLinksQuery{
+		CrawlResultID: id,
+		CurrPage:      currPage,
+		PageSize:      pageSize,
+		Filter: domain.LinkFilter{
+			Classification: c.Query("classification"),
+			Scheme:         c.Query("scheme"),
+			Rel:            strings.ToLower(c.Query("rel")),
+			Query:          c.Query("query"),
+		},
+	}
+
+	response, err := h.crawlService.GetLinks(query)
+	if err != nil {
+		respondCrawlResultError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"list": response.List, "total_count": response.TotalCount})
+}
+
+// GetImages handles the request to list the image inventory of a crawl result
+func (h *CrawlHandler) GetImages(c *gin.Context) {
+	id, ok := parseCrawlResultID(c)
+	if !ok {
+		return
+	}
+
+	images, err := h.crawlService.GetImages(id)
+	if err != nil {
+		respondCrawlResultError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"list": images, "total_count": len(images)})
+}
+
+// GetAccessibilityIssues handles the request to list the accessibility issues of a crawl result
+func (h *CrawlHandler) GetAccessibilityIssues(c *gin.Context) {
+	id, ok := parseCrawlResultID(c)
+	if !ok {
+		return
+	}
+
+	issues, err := h.crawlService.GetAccessibilityIssues(id, c.Query("severity"))
+	if err != nil {
+		respondCrawlResultError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"list": issues, "total_count": len(issues)})
+}
+
+// GetMixedContent handles the request to list the mixed content of a crawl result
+func (h *CrawlHandler) GetMixedContent(c *gin.Context) {
+	id, ok := parseCrawlResultID(c)
+	if !ok {
+		return
+	}
+
+	items, err := h.crawlService.GetMixedContent(id, c.Query("type"))
+	if err != nil {
+		respondCrawlResultError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"list": items, "total_count": len(items)})
+}
+
+// GetHeadingOutline handles the request to get the heading outline of a crawl result with its derived checks
+func (h *CrawlHandler) GetHeadingOutline(c *gin.Context) {
+	id, ok := parseCrawlResultID(c)
+	if !ok {
+		return
+	}
+
+	result, headings, err := h.crawlService.GetHeadingOutline(id)
+	if err != nil {
+		respondCrawlResultError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":                headings,
+		"total_count":         len(headings),
+		"multiple_h1":         result.MultipleH1,
+		"missing_h1":          result.MissingH1,
+		"heading_level_skips": result.HeadingLevelSkips,
+	})
+}
+
+// GetStructuredData handles the request to list the structured data entities of a crawl result
+func (h *CrawlHandler) GetStructuredData(c *gin.Context) {
+	id, ok := parseCrawlResultID(c)
+	if !ok {
+		return
+	}
+
+	entities, issues, err := h.crawlService.GetStructuredData(id)
+	if err != nil {
+		respondCrawlResultError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"list": entities, "issues": issues, "total_count": len(entities)})
+}
+
+// GetSecurityReport handles the request to get the security report of a crawl result and its regressions
+func (h *CrawlHandler) GetSecurityReport(c *gin.Context) {
+	id, ok := parseCrawlResultID(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.crawlService.GetSecurityReport(id)
+	if err != nil {
+		if errors.Is(err, domain.ErrSecurityReportNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": domain.ErrSecurityReportNotFound.Error()})
+			return
+		}
+		respondCrawlResultError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetCrawlSession handles the request to get a site crawl session with all of its pages
+func (h *CrawlHandler) GetCrawlSession(c *gin.Context) {
+	id, ok := parseCrawlResultID(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.crawlService.GetCrawlSession(id)
+	if err != nil {
+		if errors.Is(err, domain.ErrCrawlSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"message": domain.ErrCrawlSessionNotFound.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// parseCrawlResultID reads the :id path parameter, writing a 400 response if it is invalid
+func parseCrawlResultID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+// respondCrawlResultError writes the error response for a failed single crawl result lookup
+func respondCrawlResultError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrCrawlResultNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"message": domain.ErrCrawlResultNotFound.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 }
 
 // GetCrawlResults handles the request to get a paginated list of crawl results
@@ -65,7 +300,6 @@ func (h *CrawlHandler) GetCrawlResults(c *gin.Context) {
 	currPageStr := c.DefaultQuery("currPage", "1")
 	pageSizeStr := c.DefaultQuery("pageSize", "10")
 	queryStr := c.DefaultQuery("query", "")
-	sortingJsonStr := c.DefaultQuery("sorting", "") // Get sorting JSON string
 
 	currPage, err := strconv.Atoi(currPageStr)
 	if err != nil || currPage < 1 {
@@ -77,20 +311,229 @@ func (h *CrawlHandler) GetCrawlResults(c *gin.Context) {
 		pageSize = 10
 	}
 
+	sorting, err := parseSorting(c.Query("sorting"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	filter, err := parseCrawlResultFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	// paging=cursor switches to keyset paging; a cursor from a previous response implies it
+	paging := c.DefaultQuery("paging", "offset")
+	if paging != "offset" && paging != "cursor" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("invalid value for paging: %q", paging)})
+		return
+	}
+
+	count := c.Query("count")
+	switch count {
+	case "", domain.CountExact, domain.CountEstimated, domain.CountNone:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("invalid value for count: %q", count)})
+		return
+	}
+
 	query := queries.GetCrawlResultsQuery{
-		CurrPage:   currPage,
-		PageSize:   pageSize,
-		Query:      queryStr,
-		SortingJson: sortingJsonStr, // Assign sorting JSON string
+		CurrPage:  currPage,
+		PageSize:  pageSize,
+		Query:     queryStr,
+		Search:    strings.TrimSpace(c.Query("search")),
+		Sorting:   sorting,
+		Filter:    filter,
+		UseCursor: paging == "cursor" || c.Query("cursor") != "",
+		Cursor:    c.Query("cursor"),
+		Count:     count,
 	}
 
 	response, err := h.crawlService.GetCrawlResults(query)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidSorting) || errors.Is(err, domain.ErrInvalidFilter) || errors.Is(err, domain.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"list": response.List, "total_count": response.TotalCount})
+	c.JSON(http.StatusOK, response)
+}
+
+// parseSorting reads the sorting query parameter. It is either an ordered list such as
+// [{"field":"status","direction":"asc"},{"field":"created_at","direction":"desc"}] or, as sent by
+// older clients, an object such as {"status":true,"created_at":false} whose keys apply in the order given.
+func parseSorting(raw string) ([]domain.SortField, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(raw, "[") {
+		var sorting []domain.SortField
+		if err := json.Unmarshal([]byte(raw), &sorting); err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidSorting, err)
+		}
+		for i := range sorting {
+			direction := strings.ToLower(sorting[i].Direction)
+			switch direction {
+			case "":
+				direction = domain.SortAscending
+			case domain.SortAscending, domain.SortDescending:
+			default:
+				return nil, fmt.Errorf("%w: direction of %q must be asc or desc", domain.ErrInvalidSorting, sorting[i].Field)
+			}
+			sorting[i].Direction = direction
+		}
+		return sorting, nil
+	}
+
+	// Decode the object token by token, since a map would lose the key order
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("%w: expected a JSON array or object", domain.ErrInvalidSorting)
+	}
+	var sorting []domain.SortField
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidSorting, err)
+		}
+		var ascending bool
+		if err := decoder.Decode(&ascending); err != nil {
+			return nil, fmt.Errorf("%w: value of %q must be a boolean", domain.ErrInvalidSorting, token)
+		}
+		direction := domain.SortDescending
+		if ascending {
+			direction = domain.SortAscending
+		}
+		sorting = append(sorting, domain.SortField{Field: token.(string), Direction: direction})
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidSorting, err)
+	}
+	return sorting, nil
+}
+
+// parseCrawlResultFilter reads the optional list filters from the query string
+func parseCrawlResultFilter(c *gin.Context) (domain.CrawlResultFilter, error) {
+	filter := domain.CrawlResultFilter{
+		Language:     c.Query("language"),
+		HTTPProtocol: c.Query("http_protocol"),
+	}
+
+	if value, ok := c.GetQuery("http_status_code"); ok {
+		statusCode, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid value for http_status_code: %q", value)
+		}
+		filter.HTTPStatusCode = statusCode
+	}
+
+	boolFilters := map[string]**bool{
+		"has_meta_description": &filter.HasMetaDescription,
+		"has_canonical":        &filter.HasCanonical,
+		"noindex":              &filter.NoIndex,
+		"has_open_graph":       &filter.HasOpenGraph,
+		"has_twitter_card":     &filter.HasTwitterCard,
+	}
+	for name, field := range boolFilters {
+		value, ok := c.GetQuery(name)
+		if !ok {
+			continue
+		}
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid value for %s: %q", name, value)
+		}
+		*field = &parsed
+	}
+
+	rangeFilters := map[string]**int64{
+		"min_ttfb_ms":       &filter.MinTTFBMs,
+		"max_ttfb_ms":       &filter.MaxTTFBMs,
+		"min_total_time_ms": &filter.MinTotalTimeMs,
+		"max_total_time_ms": &filter.MaxTotalTimeMs,
+	}
+	for name, field := range rangeFilters {
+		value, ok := c.GetQuery(name)
+		if !ok {
+			continue
+		}
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("invalid value for %s: %q", name, value)
+		}
+		*field = &parsed
+	}
+
+	expression, err := parseFilterTerms(c)
+	if err != nil {
+		return filter, err
+	}
+	filter.Expression = expression
+
+	return filter, nil
+}
+
+// parseFilterTerms combines the typed filter parameters and the filter expression parameter into one expression
+func parseFilterTerms(c *gin.Context) (*domain.FilterExpression, error) {
+	var terms []domain.FilterExpression
+
+	equalityFilters := []string{"html_version", "has_login_form", "has_error", "host"}
+	for _, name := range equalityFilters {
+		if value, ok := c.GetQuery(name); ok {
+			terms = append(terms, domain.FilterExpression{Op: domain.FilterEq, Field: name, Value: value})
+		}
+	}
+
+	// status=done,error matches any of the listed statuses
+	if value, ok := c.GetQuery("status"); ok {
+		var statuses []domain.FilterExpression
+		for _, status := range strings.Split(value, ",") {
+			statuses = append(statuses, domain.FilterExpression{Op: domain.FilterEq, Field: "status", Value: strings.TrimSpace(status)})
+		}
+		terms = append(terms, domain.FilterExpression{Op: domain.FilterOr, Operands: statuses})
+	}
+
+	rangeFilters := []struct {
+		param string
+		field string
+		op    string
+	}{
+		{"created_from", "created_at", domain.FilterGte},
+		{"created_to", "created_at", domain.FilterLte},
+		{"min_internal_link_count", "internal_link_count", domain.FilterGte},
+		{"max_internal_link_count", "internal_link_count", domain.FilterLte},
+		{"min_external_link_count", "external_link_count", domain.FilterGte},
+		{"max_external_link_count", "external_link_count", domain.FilterLte},
+		{"min_inaccessible_link_count", "inaccessible_link_count", domain.FilterGte},
+		{"max_inaccessible_link_count", "inaccessible_link_count", domain.FilterLte},
+	}
+	for _, rangeFilter := range rangeFilters {
+		if value, ok := c.GetQuery(rangeFilter.param); ok {
+			terms = append(terms, domain.FilterExpression{Op: rangeFilter.op, Field: rangeFilter.field, Value: value})
+		}
+	}
+
+	expression, err := parseFilterExpression(c.Query("filter"))
+	if err != nil {
+		return nil, err
+	}
+	if expression != nil {
+		terms = append(terms, *expression)
+	}
+
+	switch len(terms) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &terms[0], nil
+	}
+	return &domain.FilterExpression{Op: domain.FilterAnd, Operands: terms}, nil
 }
 
 // DeleteCrawlResults handles the request to delete multiple crawl results by IDs
@@ -117,3 +560,57 @@ func (h *CrawlHandler) DeleteCrawlResults(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Crawl results deleted successfully"})
 }
+
+// CancelCrawls handles the request to stop multiple queued or running crawl jobs by IDs
+func (h *CrawlHandler) CancelCrawls(c *gin.Context) {
+	var req domain.CancelCrawlsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	cmd := commands.CancelCrawlsCommand{
+		IDs: req.IDs,
+	}
+
+	ids, err := h.crawlService.CancelCrawls(cmd)
+	if err != nil {
+		if errors.Is(err, domain.ErrNoCancellableCrawls) {
+			c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to cancel crawl jobs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Crawl jobs cancelled successfully", "ids": ids})
+}
+
+// RequeueCrawls handles the request to re-run multiple finished crawl jobs by IDs
+func (h *CrawlHandler) RequeueCrawls(c *gin.Context) {
+	var req domain.RequeueCrawlsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	cmd := commands.RequeueCrawlsCommand{
+		IDs: req.IDs,
+	}
+
+	ids, err := h.crawlService.RequeueCrawls(cmd)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNoRequeueableCrawls):
+			c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+			return
+		case errors.Is(err, domain.ErrCrawlQueueFull), errors.Is(err, domain.ErrCrawlQueueClosed):
+			c.JSON(http.StatusServiceUnavailable, gin.H{"message": err.Error(), "ids": ids})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to re-queue crawl jobs"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Crawl jobs re-queued successfully", "ids": ids})
+}
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"backend/domain"
+)
+
+const (
+	maxFilterComparisons = 50 // Bounds the size of a filter expression
+	maxFilterDepth       = 10 // Bounds the nesting of parentheses and NOT, which the parser recurses into
+)
+
+// parseFilterExpression parses the filter query parameter, e.g.
+//
+//	status:done AND inaccessible_link_count>0 AND created_at>=-7d
+//	(has_login_form:true OR host:example.com) AND NOT html_version:"HTML 4.01"
+//
+// Comparisons are field, operator and value without spaces in between; values with spaces are quoted.
+// ":" is a synonym for "=". NOT binds tighter than AND, which binds tighter than OR.
+// Fields and values are checked by the repository, which knows their types.
+func parseFilterExpression(input string) (*domain.FilterExpression, error) {
+	tokens, err := tokenizeFilter(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	p := &filterParser{tokens: tokens}
+	expression, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("%w: unexpected %q", domain.ErrInvalidFilter, p.tokens[p.pos].text)
+	}
+	return &expression, nil
+}
+
+type filterTokenKind int
+
+const (
+	filterTokenWord filterTokenKind = iota
+	filterTokenOpen
+	filterTokenClose
+	filterTokenComparison
+)
+
+type filterToken struct {
+	kind       filterTokenKind
+	text       string
+	comparison domain.FilterExpression // Set for filterTokenComparison
+}
+
+// tokenizeFilter splits the input into parentheses, keywords and comparisons
+func tokenizeFilter(input string) ([]filterToken, error) {
+	var tokens []filterToken
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		switch r := runes[i]; {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, filterToken{kind: filterTokenOpen, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, filterToken{kind: filterTokenClose, text: ")"})
+			i++
+		default:
+			start := i
+			for i < len(runes) && isFilterFieldRune(runes[i]) {
+				i++
+			}
+			field := string(runes[start:i])
+			if field == "" {
+				return nil, fmt.Errorf("%w: unexpected %q", domain.ErrInvalidFilter, string(r))
+			}
+
+			op := ""
+			for _, candidate := range []string{">=", "<=", "!=", "=", ":", ">", "<", "~"} {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				tokens = append(tokens, filterToken{kind: filterTokenWord, text: field})
+				continue
+			}
+			i += len([]rune(op))
+			if op == ":" {
+				op = domain.FilterEq
+			}
+
+			value, next, err := readFilterValue(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			i = next
+			tokens = append(tokens, filterToken{
+				kind:       filterTokenComparison,
+				text:       string(runes[start:i]),
+				comparison: domain.FilterExpression{Op: op, Field: field, Value: value},
+			})
+		}
+	}
+	return tokens, nil
+}
+
+// readFilterValue reads a bare or double-quoted value starting at runes[i]; quotes may contain \" and \\
+func readFilterValue(runes []rune, i int) (string, int, error) {
+	if i < len(runes) && runes[i] == '"' {
+		var value strings.Builder
+		for i++; i < len(runes); i++ {
+			switch runes[i] {
+			case '\\':
+				if i+1 < len(runes) {
+					i++
+					value.WriteRune(runes[i])
+				}
+			case '"':
+				return value.String(), i + 1, nil
+			default:
+				value.WriteRune(runes[i])
+			}
+		}
+		return "", i, fmt.Errorf("%w: unterminated quoted value", domain.ErrInvalidFilter)
+	}
+
+	start := i
+	for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' {
+		i++
+	}
+	if start == i {
+		return "", i, fmt.Errorf("%w: missing value after %q", domain.ErrInvalidFilter, string(runes[:start]))
+	}
+	return string(runes[start:i]), i, nil
+}
+
+func isFilterFieldRune(r rune) bool {
+	return r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
+
+// filterParser is a recursive descent parser over the tokens of a filter expression
+type filterParser struct {
+	tokens      []filterToken
+	pos         int
+	comparisons int
+	depth       int // Parentheses and NOTs the current position is nested in
+}
+
+func (p *filterParser) parseOr() (domain.FilterExpression, error) {
+	return p.parseBinary(domain.FilterOr, p.parseAnd)
+}
+
+func (p *filterParser) parseAnd() (domain.FilterExpression, error) {
+	return p.parseBinary(domain.FilterAnd, p.parseUnary)
+}
+
+// parseBinary parses operands joined by the keyword op, flattening them into a single node
+func (p *filterParser) parseBinary(op string, parseOperand func() (domain.FilterExpression, error)) (domain.FilterExpression, error) {
+	first, err := parseOperand()
+	if err != nil {
+		return domain.FilterExpression{}, err
+	}
+	operands := []domain.FilterExpression{first}
+	for p.keyword(op) {
+		p.pos++
+		operand, err := parseOperand()
+		if err != nil {
+			return domain.FilterExpression{}, err
+		}
+		operands = append(operands, operand)
+	}
+	if len(operands) == 1 {
+		return first, nil
+	}
+	return domain.FilterExpression{Op: op, Operands: operands}, nil
+}
+
+func (p *filterParser) parseUnary() (domain.FilterExpression, error) {
+	if p.pos >= len(p.tokens) {
+		return domain.FilterExpression{}, fmt.Errorf("%w: unexpected end of expression", domain.ErrInvalidFilter)
+	}
+
+	token := p.tokens[p.pos]
+	if p.keyword(domain.FilterNot) || token.kind == filterTokenOpen {
+		if p.depth >= maxFilterDepth {
+			return domain.FilterExpression{}, fmt.Errorf("%w: nested more than %d levels deep", domain.ErrInvalidFilter, maxFilterDepth)
+		}
+		p.depth++
+		defer func() { p.depth-- }()
+	}
+
+	switch {
+	case p.keyword(domain.FilterNot):
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return domain.FilterExpression{}, err
+		}
+		return domain.FilterExpression{Op: domain.FilterNot, Operands: []domain.FilterExpression{operand}}, nil
+	case token.kind == filterTokenOpen:
+		p.pos++
+		expression, err := p.parseOr()
+		if err != nil {
+			return domain.FilterExpression{}, err
+		}
+		if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != filterTokenClose {
+			return domain.FilterExpression{}, fmt.Errorf("%w: missing closing parenthesis", domain.ErrInvalidFilter)
+		}
+		p.pos++
+		return expression, nil
+	case token.kind == filterTokenComparison:
+		p.pos++
+		p.comparisons++
+		if p.comparisons > maxFilterComparisons {
+			return domain.FilterExpression{}, fmt.Errorf("%w: more than %d comparisons", domain.ErrInvalidFilter, maxFilterComparisons)
+		}
+		return token.comparison, nil
+	}
+	return domain.FilterExpression{}, fmt.Errorf("%w: unexpected %q", domain.ErrInvalidFilter, token.text)
+}
+
+// keyword reports whether the current token is the keyword, e.g. AND
+func (p *filterParser) keyword(keyword string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == filterTokenWord && strings.EqualFold(p.tokens[p.pos].text, keyword)
+}
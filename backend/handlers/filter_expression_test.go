@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"backend/domain"
+)
+
+func comparison(field, op, value string) domain.FilterExpression {
+	return domain.FilterExpression{Op: op, Field: field, Value: value}
+}
+
+func and(operands ...domain.FilterExpression) domain.FilterExpression {
+	return domain.FilterExpression{Op: domain.FilterAnd, Operands: operands}
+}
+
+func or(operands ...domain.FilterExpression) domain.FilterExpression {
+	return domain.FilterExpression{Op: domain.FilterOr, Operands: operands}
+}
+
+func not(operand domain.FilterExpression) domain.FilterExpression {
+	return domain.FilterExpression{Op: domain.FilterNot, Operands: []domain.FilterExpression{operand}}
+}
+
+func ptr(expression domain.FilterExpression) *domain.FilterExpression {
+	return &expression
+}
+
+func TestParseFilterExpression(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  *domain.FilterExpression
+	}{
+		{"empty", "", nil},
+		{"only spaces", "   ", nil},
+		{"colon is equals", "status:done", &domain.FilterExpression{Op: domain.FilterEq, Field: "status", Value: "done"}},
+		{"operators", "a>=1 AND b<=2 AND c!=3 AND d>4 AND e<5 AND f=6 AND g~x", ptr(and(
+			comparison("a", domain.FilterGte, "1"),
+			comparison("b", domain.FilterLte, "2"),
+			comparison("c", domain.FilterNe, "3"),
+			comparison("d", domain.FilterGt, "4"),
+			comparison("e", domain.FilterLt, "5"),
+			comparison("f", domain.FilterEq, "6"),
+			comparison("g", domain.FilterContains, "x"),
+		))},
+		{"relative time value", "created_at>=-7d", ptr(comparison("created_at", domain.FilterGte, "-7d"))},
+		{"keywords are case insensitive", "a:1 and b:2 Or c:3", ptr(or(
+			and(comparison("a", domain.FilterEq, "1"), comparison("b", domain.FilterEq, "2")),
+			comparison("c", domain.FilterEq, "3"),
+		))},
+		{"and binds tighter than or", "a:1 OR b:2 AND c:3", ptr(or(
+			comparison("a", domain.FilterEq, "1"),
+			and(comparison("b", domain.FilterEq, "2"), comparison("c", domain.FilterEq, "3")),
+		))},
+		{"not binds tighter than and", "NOT a:1 AND b:2", ptr(and(
+			not(comparison("a", domain.FilterEq, "1")),
+			comparison("b", domain.FilterEq, "2"),
+		))},
+		{"parentheses override precedence", "(a:1 OR b:2) AND c:3", ptr(and(
+			or(comparison("a", domain.FilterEq, "1"), comparison("b", domain.FilterEq, "2")),
+			comparison("c", domain.FilterEq, "3"),
+		))},
+		{"not of a group", "NOT (a:1 OR b:2)", ptr(not(
+			or(comparison("a", domain.FilterEq, "1"), comparison("b", domain.FilterEq, "2")),
+		))},
+		{"double not", "NOT NOT a:1", ptr(not(not(comparison("a", domain.FilterEq, "1"))))},
+		{"operands are flattened", "a:1 AND b:2 AND c:3", ptr(and(
+			comparison("a", domain.FilterEq, "1"),
+			comparison("b", domain.FilterEq, "2"),
+			comparison("c", domain.FilterEq, "3"),
+		))},
+		{"value ends at parenthesis", "(a:1)", ptr(comparison("a", domain.FilterEq, "1"))},
+		{"quoted value with spaces", `html_version:"HTML 4.01"`, ptr(comparison("html_version", domain.FilterEq, "HTML 4.01"))},
+		{"quoted value with escapes", `page_title:"say \"hi\" \\ bye"`, ptr(comparison("page_title", domain.FilterEq, `say "hi" \ bye`))},
+		{"quoted keyword is a value", `page_title:"AND"`, ptr(comparison("page_title", domain.FilterEq, "AND"))},
+		{"quoted parentheses are a value", `page_title:"(draft)"`, ptr(comparison("page_title", domain.FilterEq, "(draft)"))},
+		{"empty quoted value", `error:""`, ptr(comparison("error", domain.FilterEq, ""))},
+		{"nesting at the limit", strings.Repeat("(", maxFilterDepth) + "a:1" + strings.Repeat(")", maxFilterDepth), ptr(comparison("a", domain.FilterEq, "1"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFilterExpression(tt.input)
+			if err != nil {
+				t.Fatalf("parseFilterExpression(%q) returned error: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseFilterExpression(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilterExpressionErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		message string
+	}{
+		{"missing value", "status:", "missing value"},
+		{"missing value before parenthesis", "(status:)", "missing value"},
+		{"unterminated quote", `page_title:"abc`, "unterminated quoted value"},
+		{"unexpected character", "status:done & a:1", `unexpected "&"`},
+		{"bare word", "done", `unexpected "done"`},
+		{"missing operand", "a:1 AND", "unexpected end of expression"},
+		{"leading keyword", "AND a:1", `unexpected "AND"`},
+		{"missing closing parenthesis", "(a:1", "missing closing parenthesis"},
+		{"extra closing parenthesis", "a:1)", `unexpected ")"`},
+		{"empty parentheses", "()", `unexpected ")"`},
+		{"missing keyword", "a:1 b:2", `unexpected "b:2"`},
+		{"dangling not", "NOT", "unexpected end of expression"},
+		{"too many comparisons", strings.Repeat("a:1 OR ", maxFilterComparisons) + "a:1", "more than 50 comparisons"},
+		{"parentheses nested too deep", strings.Repeat("(", maxFilterDepth+1) + "a:1" + strings.Repeat(")", maxFilterDepth+1), "nested more than 10 levels"},
+		{"nots nested too deep", strings.Repeat("NOT ", maxFilterDepth+1) + "a:1", "nested more than 10 levels"},
+		{"mixed nesting too deep", strings.Repeat("NOT (", maxFilterDepth/2+1) + "a:1" + strings.Repeat(")", maxFilterDepth/2+1), "nested more than 10 levels"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFilterExpression(tt.input)
+			if !errors.Is(err, domain.ErrInvalidFilter) {
+				t.Fatalf("parseFilterExpression(%q) = %+v, %v; want ErrInvalidFilter", tt.input, got, err)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("parseFilterExpression(%q) error = %q, want it to contain %q", tt.input, err, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+-- Schema of the crawler as it existed before migrations were introduced. The table is created only if
+-- missing, so databases that were set up by hand adopt this version without changes.
+
+CREATE TABLE IF NOT EXISTS crawl_results (
+    id                      INT AUTO_INCREMENT PRIMARY KEY,
+    html_version            VARCHAR(64)   NOT NULL DEFAULT '',
+    url                     VARCHAR(2048) NULL,
+    page_title              TEXT          NOT NULL,
+    heading_counts          JSON          NULL,
+    internal_link_count     INT           NOT NULL DEFAULT 0,
+    external_link_count     INT           NOT NULL DEFAULT 0,
+    inaccessible_link_count INT           NOT NULL DEFAULT 0,
+    has_login_form          BOOLEAN       NOT NULL DEFAULT FALSE,
+    error                   TEXT          NOT NULL,
+    created_at              TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,3 @@
+ALTER TABLE crawl_results
+    DROP KEY idx_crawl_results_status,
+    DROP COLUMN status;
@@ -0,0 +1,10 @@
+-- Crawls are queued and processed in the background. Results stored before then were finished
+-- synchronously, so they are marked done or error instead of taking the queued default.
+
+ALTER TABLE crawl_results
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'done',
+    ADD KEY idx_crawl_results_status (status);
+
+UPDATE crawl_results SET status = 'error' WHERE error <> '';
+
+ALTER TABLE crawl_results ALTER COLUMN status SET DEFAULT 'queued';
@@ -0,0 +1,8 @@
+CREATE TABLE crawl_broken_links (
+    id              INT AUTO_INCREMENT PRIMARY KEY,
+    crawl_result_id INT           NOT NULL,
+    url             VARCHAR(2048) NOT NULL,
+    status_code     INT           NOT NULL DEFAULT 0,
+    error           TEXT          NOT NULL,
+    CONSTRAINT fk_crawl_broken_links_result FOREIGN KEY (crawl_result_id) REFERENCES crawl_results (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,3 @@
+ALTER TABLE crawl_results
+    DROP COLUMN login_form_action,
+    DROP COLUMN login_form_confidence;
@@ -0,0 +1,3 @@
+ALTER TABLE crawl_results
+    ADD COLUMN login_form_confidence DOUBLE        NOT NULL DEFAULT 0,
+    ADD COLUMN login_form_action     VARCHAR(2048) NOT NULL DEFAULT '';
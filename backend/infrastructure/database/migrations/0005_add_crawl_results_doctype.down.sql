@@ -0,0 +1,3 @@
+ALTER TABLE crawl_results
+    DROP COLUMN document_mode,
+    DROP COLUMN has_doctype;
@@ -0,0 +1,3 @@
+ALTER TABLE crawl_results
+    ADD COLUMN has_doctype   BOOLEAN     NOT NULL DEFAULT FALSE,
+    ADD COLUMN document_mode VARCHAR(32) NOT NULL DEFAULT '';
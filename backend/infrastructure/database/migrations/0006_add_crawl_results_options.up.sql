@@ -0,0 +1 @@
+ALTER TABLE crawl_results ADD COLUMN options JSON NULL;
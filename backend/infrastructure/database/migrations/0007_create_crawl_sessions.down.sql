@@ -0,0 +1,7 @@
+ALTER TABLE crawl_results DROP FOREIGN KEY fk_crawl_results_session;
+
+ALTER TABLE crawl_results
+    DROP COLUMN depth,
+    DROP COLUMN session_id;
+
+DROP TABLE IF EXISTS crawl_sessions;
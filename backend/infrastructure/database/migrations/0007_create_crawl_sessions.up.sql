@@ -0,0 +1,13 @@
+CREATE TABLE crawl_sessions (
+    id         INT AUTO_INCREMENT PRIMARY KEY,
+    root_url   VARCHAR(2048) NOT NULL,
+    status     VARCHAR(20)   NOT NULL DEFAULT 'queued',
+    max_depth  INT           NOT NULL DEFAULT 0,
+    max_pages  INT           NOT NULL DEFAULT 0,
+    created_at TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE crawl_results
+    ADD COLUMN session_id INT NULL,
+    ADD COLUMN depth      INT NOT NULL DEFAULT 0,
+    ADD CONSTRAINT fk_crawl_results_session FOREIGN KEY (session_id) REFERENCES crawl_sessions (id) ON DELETE SET NULL;
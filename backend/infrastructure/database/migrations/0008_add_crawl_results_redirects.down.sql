@@ -0,0 +1,3 @@
+ALTER TABLE crawl_results
+    DROP COLUMN redirect_chain,
+    DROP COLUMN final_url;
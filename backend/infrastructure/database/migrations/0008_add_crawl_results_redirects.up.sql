@@ -0,0 +1,3 @@
+ALTER TABLE crawl_results
+    ADD COLUMN final_url      VARCHAR(2048) NOT NULL DEFAULT '',
+    ADD COLUMN redirect_chain JSON          NULL;
@@ -0,0 +1 @@
+ALTER TABLE crawl_results DROP COLUMN charset;
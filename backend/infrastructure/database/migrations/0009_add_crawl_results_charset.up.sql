@@ -0,0 +1 @@
+ALTER TABLE crawl_results ADD COLUMN charset VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,10 @@
+ALTER TABLE crawl_results
+    DROP COLUMN favicon_url,
+    DROP COLUMN twitter_card,
+    DROP COLUMN open_graph,
+    DROP COLUMN language,
+    DROP COLUMN viewport,
+    DROP COLUMN meta_robots,
+    DROP COLUMN canonical_url,
+    DROP COLUMN meta_keywords,
+    DROP COLUMN meta_description;
@@ -0,0 +1,10 @@
+ALTER TABLE crawl_results
+    ADD COLUMN meta_description TEXT          NOT NULL,
+    ADD COLUMN meta_keywords    TEXT          NOT NULL,
+    ADD COLUMN canonical_url    VARCHAR(2048) NOT NULL DEFAULT '',
+    ADD COLUMN meta_robots      VARCHAR(255)  NOT NULL DEFAULT '',
+    ADD COLUMN viewport         VARCHAR(255)  NOT NULL DEFAULT '',
+    ADD COLUMN language         VARCHAR(35)   NOT NULL DEFAULT '',
+    ADD COLUMN open_graph       JSON          NULL,
+    ADD COLUMN twitter_card     JSON          NULL,
+    ADD COLUMN favicon_url      VARCHAR(2048) NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS crawl_structured_data_issues;
+DROP TABLE IF EXISTS crawl_structured_data;
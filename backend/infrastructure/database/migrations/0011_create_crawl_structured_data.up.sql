@@ -0,0 +1,16 @@
+CREATE TABLE crawl_structured_data (
+    id              INT AUTO_INCREMENT PRIMARY KEY,
+    crawl_result_id INT         NOT NULL,
+    format          VARCHAR(16) NOT NULL,
+    types           JSON        NULL,
+    properties      JSON        NULL,
+    CONSTRAINT fk_crawl_structured_data_result FOREIGN KEY (crawl_result_id) REFERENCES crawl_results (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE crawl_structured_data_issues (
+    id              INT AUTO_INCREMENT PRIMARY KEY,
+    crawl_result_id INT         NOT NULL,
+    format          VARCHAR(16) NOT NULL,
+    message         TEXT        NOT NULL,
+    CONSTRAINT fk_crawl_structured_data_issues_result FOREIGN KEY (crawl_result_id) REFERENCES crawl_results (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
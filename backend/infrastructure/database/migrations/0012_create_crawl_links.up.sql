@@ -0,0 +1,13 @@
+CREATE TABLE crawl_links (
+    id              INT AUTO_INCREMENT PRIMARY KEY,
+    crawl_result_id INT           NOT NULL,
+    url             VARCHAR(2048) NOT NULL,
+    href            TEXT          NOT NULL,
+    anchor_text     TEXT          NOT NULL,
+    rel             VARCHAR(255)  NOT NULL DEFAULT '',
+    target          VARCHAR(255)  NOT NULL DEFAULT '',
+    scheme          VARCHAR(32)   NOT NULL DEFAULT '',
+    classification  VARCHAR(16)   NOT NULL DEFAULT '',
+    KEY idx_crawl_links_classification (crawl_result_id, classification),
+    CONSTRAINT fk_crawl_links_result FOREIGN KEY (crawl_result_id) REFERENCES crawl_results (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,8 @@
+ALTER TABLE crawl_results
+    DROP COLUMN broken_image_count,
+    DROP COLUMN images_missing_dimensions_count,
+    DROP COLUMN images_empty_alt_count,
+    DROP COLUMN images_missing_alt_count,
+    DROP COLUMN image_count;
+
+DROP TABLE IF EXISTS crawl_images;
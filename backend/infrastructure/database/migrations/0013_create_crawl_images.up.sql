@@ -0,0 +1,24 @@
+CREATE TABLE crawl_images (
+    id              INT AUTO_INCREMENT PRIMARY KEY,
+    crawl_result_id INT           NOT NULL,
+    url             VARCHAR(2048) NOT NULL,
+    element         VARCHAR(16)   NOT NULL,
+    attribute       VARCHAR(16)   NOT NULL,
+    descriptor      VARCHAR(32)   NOT NULL DEFAULT '',
+    has_alt         BOOLEAN       NOT NULL DEFAULT FALSE,
+    alt             TEXT          NOT NULL,
+    width           VARCHAR(32)   NOT NULL DEFAULT '',
+    height          VARCHAR(32)   NOT NULL DEFAULT '',
+    status_code     INT           NOT NULL DEFAULT 0,
+    byte_size       BIGINT        NOT NULL DEFAULT -1,
+    broken          BOOLEAN       NOT NULL DEFAULT FALSE,
+    error           TEXT          NOT NULL,
+    CONSTRAINT fk_crawl_images_result FOREIGN KEY (crawl_result_id) REFERENCES crawl_results (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE crawl_results
+    ADD COLUMN image_count                     INT NOT NULL DEFAULT 0,
+    ADD COLUMN images_missing_alt_count        INT NOT NULL DEFAULT 0,
+    ADD COLUMN images_empty_alt_count          INT NOT NULL DEFAULT 0,
+    ADD COLUMN images_missing_dimensions_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN broken_image_count              INT NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+ALTER TABLE crawl_results
+    DROP COLUMN accessibility_warning_count,
+    DROP COLUMN accessibility_error_count;
+
+DROP TABLE IF EXISTS crawl_accessibility_issues;
@@ -0,0 +1,13 @@
+CREATE TABLE crawl_accessibility_issues (
+    id              INT AUTO_INCREMENT PRIMARY KEY,
+    crawl_result_id INT         NOT NULL,
+    rule_id         VARCHAR(64) NOT NULL,
+    severity        VARCHAR(16) NOT NULL,
+    message         TEXT        NOT NULL,
+    selector        TEXT        NOT NULL,
+    CONSTRAINT fk_crawl_accessibility_issues_result FOREIGN KEY (crawl_result_id) REFERENCES crawl_results (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE crawl_results
+    ADD COLUMN accessibility_error_count   INT NOT NULL DEFAULT 0,
+    ADD COLUMN accessibility_warning_count INT NOT NULL DEFAULT 0;
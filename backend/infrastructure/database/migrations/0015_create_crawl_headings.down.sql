@@ -0,0 +1,6 @@
+ALTER TABLE crawl_results
+    DROP COLUMN heading_level_skips,
+    DROP COLUMN missing_h1,
+    DROP COLUMN multiple_h1;
+
+DROP TABLE IF EXISTS crawl_headings;
@@ -0,0 +1,13 @@
+CREATE TABLE crawl_headings (
+    id              INT AUTO_INCREMENT PRIMARY KEY,
+    crawl_result_id INT     NOT NULL,
+    level           TINYINT NOT NULL,
+    text            TEXT    NOT NULL,
+    position        INT     NOT NULL,
+    CONSTRAINT fk_crawl_headings_result FOREIGN KEY (crawl_result_id) REFERENCES crawl_results (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE crawl_results
+    ADD COLUMN multiple_h1         BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN missing_h1          BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN heading_level_skips INT     NOT NULL DEFAULT 0;
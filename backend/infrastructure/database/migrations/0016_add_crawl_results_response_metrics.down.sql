@@ -0,0 +1,13 @@
+ALTER TABLE crawl_results
+    DROP COLUMN total_time_ms,
+    DROP COLUMN download_ms,
+    DROP COLUMN ttfb_ms,
+    DROP COLUMN tls_handshake_ms,
+    DROP COLUMN connect_ms,
+    DROP COLUMN dns_lookup_ms,
+    DROP COLUMN body_size,
+    DROP COLUMN transfer_size,
+    DROP COLUMN content_length,
+    DROP COLUMN content_type,
+    DROP COLUMN http_protocol,
+    DROP COLUMN http_status_code;
@@ -0,0 +1,13 @@
+ALTER TABLE crawl_results
+    ADD COLUMN http_status_code INT          NOT NULL DEFAULT 0,
+    ADD COLUMN http_protocol    VARCHAR(16)  NOT NULL DEFAULT '',
+    ADD COLUMN content_type     VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN content_length   BIGINT       NOT NULL DEFAULT -1,
+    ADD COLUMN transfer_size    BIGINT       NOT NULL DEFAULT 0,
+    ADD COLUMN body_size        BIGINT       NOT NULL DEFAULT 0,
+    ADD COLUMN dns_lookup_ms    BIGINT       NOT NULL DEFAULT 0,
+    ADD COLUMN connect_ms       BIGINT       NOT NULL DEFAULT 0,
+    ADD COLUMN tls_handshake_ms BIGINT       NOT NULL DEFAULT 0,
+    ADD COLUMN ttfb_ms          BIGINT       NOT NULL DEFAULT 0,
+    ADD COLUMN download_ms      BIGINT       NOT NULL DEFAULT 0,
+    ADD COLUMN total_time_ms    BIGINT       NOT NULL DEFAULT 0;
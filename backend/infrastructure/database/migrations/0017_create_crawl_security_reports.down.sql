@@ -0,0 +1,6 @@
+ALTER TABLE crawl_results
+    DROP KEY idx_crawl_results_url,
+    DROP COLUMN security_grade,
+    DROP COLUMN security_score;
+
+DROP TABLE IF EXISTS crawl_security_reports;
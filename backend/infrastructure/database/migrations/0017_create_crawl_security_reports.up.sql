@@ -0,0 +1,19 @@
+-- The URL index serves the lookup of the previous report of a page, to show what changed
+
+CREATE TABLE crawl_security_reports (
+    id              INT AUTO_INCREMENT PRIMARY KEY,
+    crawl_result_id INT        NOT NULL,
+    score           INT        NOT NULL,
+    grade           VARCHAR(2) NOT NULL,
+    tls             JSON       NULL,
+    checks          JSON       NULL,
+    cookies         JSON       NULL,
+    created_at      TIMESTAMP  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_crawl_security_reports_result (crawl_result_id),
+    CONSTRAINT fk_crawl_security_reports_result FOREIGN KEY (crawl_result_id) REFERENCES crawl_results (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE crawl_results
+    ADD COLUMN security_score INT        NULL,
+    ADD COLUMN security_grade VARCHAR(2) NOT NULL DEFAULT '',
+    ADD KEY idx_crawl_results_url (url(255));
@@ -0,0 +1,5 @@
+ALTER TABLE crawl_results
+    DROP COLUMN passive_mixed_content_count,
+    DROP COLUMN active_mixed_content_count;
+
+DROP TABLE IF EXISTS crawl_mixed_content;
@@ -0,0 +1,13 @@
+CREATE TABLE crawl_mixed_content (
+    id              INT AUTO_INCREMENT PRIMARY KEY,
+    crawl_result_id INT           NOT NULL,
+    url             VARCHAR(2048) NOT NULL,
+    element         VARCHAR(32)   NOT NULL,
+    attribute       VARCHAR(32)   NOT NULL,
+    type            VARCHAR(16)   NOT NULL,
+    CONSTRAINT fk_crawl_mixed_content_result FOREIGN KEY (crawl_result_id) REFERENCES crawl_results (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE crawl_results
+    ADD COLUMN active_mixed_content_count  INT NOT NULL DEFAULT 0,
+    ADD COLUMN passive_mixed_content_count INT NOT NULL DEFAULT 0;
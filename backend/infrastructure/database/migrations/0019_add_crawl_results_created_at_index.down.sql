@@ -0,0 +1 @@
+ALTER TABLE crawl_results DROP KEY idx_crawl_results_created_at;
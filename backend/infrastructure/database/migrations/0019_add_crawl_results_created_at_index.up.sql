@@ -0,0 +1,3 @@
+-- Lists sorted by creation time, and keyset pages of them, seek on this index instead of sorting every row
+
+ALTER TABLE crawl_results ADD KEY idx_crawl_results_created_at (created_at);
@@ -0,0 +1,22 @@
+-- Text of crawled pages for the full-text search. MATCH needs an index on exactly the columns it
+-- lists, so the title, which is weighted separately, has an index of its own.
+
+CREATE TABLE crawl_search_documents (
+    crawl_result_id  INT        NOT NULL PRIMARY KEY,
+    title            TEXT       NOT NULL,
+    meta_description TEXT       NOT NULL,
+    headings         TEXT       NOT NULL,
+    body             MEDIUMTEXT NOT NULL,
+    FULLTEXT KEY ft_crawl_search_documents_title (title),
+    FULLTEXT KEY ft_crawl_search_documents_all (title, meta_description, headings, body),
+    CONSTRAINT fk_crawl_search_documents_result FOREIGN KEY (crawl_result_id) REFERENCES crawl_results (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Index the pages crawled before search existed by their title, meta description and headings.
+-- Their visible text was never stored, so matches in the body need a re-crawl.
+INSERT INTO crawl_search_documents (crawl_result_id, title, meta_description, headings, body)
+SELECT r.id, r.page_title, r.meta_description,
+    COALESCE((SELECT GROUP_CONCAT(h.text ORDER BY h.position SEPARATOR '\n') FROM crawl_headings h WHERE h.crawl_result_id = r.id), ''),
+    ''
+FROM crawl_results r
+WHERE r.status = 'done';
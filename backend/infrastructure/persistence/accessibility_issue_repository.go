@@ -0,0 +1,82 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+
+	"backend/domain"
+)
+
+// AccessibilityIssueRepository defines the interface for storing the accessibility issues of a CrawlResult
+type AccessibilityIssueRepository interface {
+	ReplaceForResult(crawlResultID int, issues []domain.AccessibilityIssue) error
+	GetByResultID(crawlResultID int, severity string) ([]domain.AccessibilityIssue, error)
+}
+
+// mysqlAccessibilityIssueRepository implements AccessibilityIssueRepository for MySQL
+type mysqlAccessibilityIssueRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLAccessibilityIssueRepository creates a new MySQLAccessibilityIssueRepository
+func NewMySQLAccessibilityIssueRepository(db *sql.DB) AccessibilityIssueRepository {
+	return &mysqlAccessibilityIssueRepository{db: db}
+}
+
+// ReplaceForResult deletes the stored accessibility issues of a CrawlResult and saves the given ones instead
+func (r *mysqlAccessibilityIssueRepository) ReplaceForResult(crawlResultID int, issues []domain.AccessibilityIssue) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows := make([][]interface{}, len(issues))
+	for i, issue := range issues {
+		rows[i] = []interface{}{issue.RuleID, issue.Severity, issue.Message, issue.Selector}
+	}
+	columns := []string{"rule_id", "severity", "message", "selector"}
+	if err := replaceChildRows(tx, "crawl_accessibility_issues", crawlResultID, columns, rows); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit accessibility issues: %w", err)
+	}
+	return nil
+}
+
+// GetByResultID retrieves the accessibility issues of a CrawlResult, optionally only those of one severity
+func (r *mysqlAccessibilityIssueRepository) GetByResultID(crawlResultID int, severity string) ([]domain.AccessibilityIssue, error) {
+	query := `
+		SELECT id, crawl_result_id, rule_id, severity, message, selector
+		FROM crawl_accessibility_issues
+		WHERE crawl_result_id = ?`
+	args := []interface{}{crawlResultID}
+	if severity != "" {
+		query += " AND severity = ?"
+		args = append(args, severity)
+	}
+	query += " ORDER BY id"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query accessibility issues: %w", err)
+	}
+	defer rows.Close()
+
+	issues := []domain.AccessibilityIssue{}
+	for rows.Next() {
+		var issue domain.AccessibilityIssue
+		if err := rows.Scan(&issue.ID, &issue.CrawlResultID, &issue.RuleID, &issue.Severity, &issue.Message, &issue.Selector); err != nil {
+			return nil, fmt.Errorf("failed to scan accessibility issue row: %w", err)
+		}
+		issues = append(issues, issue)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after scanning rows: %w", err)
+	}
+
+	return issues, nil
+}
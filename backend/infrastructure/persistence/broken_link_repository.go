@@ -0,0 +1,75 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+
+	"backend/domain"
+)
+
+// BrokenLinkRepository defines the interface for storing the broken links of a CrawlResult
+type BrokenLinkRepository interface {
+	ReplaceForResult(crawlResultID int, links []domain.BrokenLink) error
+	GetByResultID(crawlResultID int) ([]domain.BrokenLink, error)
+}
+
+// mysqlBrokenLinkRepository implements BrokenLinkRepository for MySQL
+type mysqlBrokenLinkRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLBrokenLinkRepository creates a new MySQLBrokenLinkRepository
+func NewMySQLBrokenLinkRepository(db *sql.DB) BrokenLinkRepository {
+	return &mysqlBrokenLinkRepository{db: db}
+}
+
+// ReplaceForResult deletes the stored broken links of a CrawlResult and saves the given ones instead
+func (r *mysqlBrokenLinkRepository) ReplaceForResult(crawlResultID int, links []domain.BrokenLink) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows := make([][]interface{}, len(links))
+	for i, link := range links {
+		rows[i] = []interface{}{link.URL, link.StatusCode, link.Error}
+	}
+	if err := replaceChildRows(tx, "crawl_broken_links", crawlResultID, []string{"url", "status_code", "error"}, rows); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit broken links: %w", err)
+	}
+	return nil
+}
+
+// GetByResultID retrieves the broken links of a CrawlResult
+func (r *mysqlBrokenLinkRepository) GetByResultID(crawlResultID int) ([]domain.BrokenLink, error) {
+	rows, err := r.db.Query(`
+		SELECT id, crawl_result_id, url, status_code, error
+		FROM crawl_broken_links
+		WHERE crawl_result_id = ?
+		ORDER BY id
+	`, crawlResultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query broken links: %w", err)
+	}
+	defer rows.Close()
+
+	links := []domain.BrokenLink{}
+	for rows.Next() {
+		var link domain.BrokenLink
+		if err := rows.Scan(&link.ID, &link.CrawlResultID, &link.URL, &link.StatusCode, &link.Error); err != nil {
+			return nil, fmt.Errorf("failed to scan broken link row: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after scanning rows: %w", err)
+	}
+
+	return links, nil
+}
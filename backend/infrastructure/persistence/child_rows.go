@@ -0,0 +1,37 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// childRowInsertBatchSize limits the rows per INSERT so results with many child rows stay below the placeholder limit
+const childRowInsertBatchSize = 500
+
+// replaceChildRows deletes the rows of a CrawlResult from table and inserts the given rows in batches.
+// Each row holds a value for every column; crawl_result_id is added in front of them.
+func replaceChildRows(tx *sql.Tx, table string, crawlResultID int, columns []string, rows [][]interface{}) error {
+	if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE crawl_result_id = ?", table), crawlResultID); err != nil {
+		return fmt.Errorf("failed to delete from %s: %w", table, err)
+	}
+
+	rowPlaceholders := "(" + strings.Repeat("?, ", len(columns)) + "?)"
+	for start := 0; start < len(rows); start += childRowInsertBatchSize {
+		batch := rows[start:min(start+childRowInsertBatchSize, len(rows))]
+
+		placeholders := strings.Repeat(rowPlaceholders+", ", len(batch)-1) + rowPlaceholders
+		query := fmt.Sprintf("INSERT INTO %s (crawl_result_id, %s) VALUES %s", table, strings.Join(columns, ", "), placeholders)
+
+		args := make([]interface{}, 0, len(batch)*(len(columns)+1))
+		for _, row := range batch {
+			args = append(args, crawlResultID)
+			args = append(args, row...)
+		}
+
+		if _, err := tx.Exec(query, args...); err != nil {
+			return fmt.Errorf("failed to insert into %s: %w", table, err)
+		}
+	}
+	return nil
+}
@@ -4,7 +4,9 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"backend/domain"
 )
@@ -12,19 +14,27 @@ import (
 // CrawlResultRepository defines the interface for storing CrawlResult
 type CrawlResultRepository interface {
 	Save(result domain.CrawlResult) (int, error)
-	GetAll(page, pageSize int, query, sortingJson string) ([]domain.CrawlResult, int, error)
-	GetTotalCount(query, sortingJson string) (int, error)
+	Update(result domain.CrawlResult) error
+	CompareAndSetStatus(id int, from []domain.CrawlStatus, to domain.CrawlStatus) (bool, error)
+	GetByID(id int) (domain.CrawlResult, error)
+	GetBySessionID(sessionID int) ([]domain.CrawlResult, error)
+	GetByStatus(statuses ...domain.CrawlStatus) ([]domain.CrawlResult, error)
+	GetAll(page, pageSize int, query string, sorting []domain.SortField, filter domain.CrawlResultFilter) ([]domain.CrawlResult, error)
+	GetPage(cursor string, pageSize int, query string, sorting []domain.SortField, filter domain.CrawlResultFilter) ([]domain.CrawlResult, string, string, error)
+	GetTotalCount(query string, filter domain.CrawlResultFilter) (int, error)
+	EstimateTotalCount(query string, filter domain.CrawlResultFilter) (int, bool, error)
 	DeleteMany(ids []int) error
 }
 
 // mysqlCrawlResultRepository implements CrawlResultRepository for MySQL
 type mysqlCrawlResultRepository struct {
-	db *sql.DB
+	db            *sql.DB
+	searchBackend SearchBackend // Matches and ranks the full-text search of a filter
 }
 
 // NewMySQLCrawlResultRepository creates a new MySQLCrawlResultRepository
-func NewMySQLCrawlResultRepository(db *sql.DB) CrawlResultRepository {
-	return &mysqlCrawlResultRepository{db: db}
+func NewMySQLCrawlResultRepository(db *sql.DB, searchBackend SearchBackend) CrawlResultRepository {
+	return &mysqlCrawlResultRepository{db: db, searchBackend: searchBackend}
 }
 
 // Save saves a CrawlResult to the database
@@ -35,12 +45,36 @@ func (r *mysqlCrawlResultRepository) Save(result domain.CrawlResult) (int, error
 		return 0, fmt.Errorf("failed to marshal heading counts: %w", err)
 	}
 
+	optionsJSON, err := json.Marshal(result.Options)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal crawl options: %w", err)
+	}
+
+	redirectChainJSON, err := json.Marshal(result.RedirectChain)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal redirect chain: %w", err)
+	}
+
+	openGraphJSON, twitterCardJSON, err := marshalSocialMetadata(result)
+	if err != nil {
+		return 0, err
+	}
+
 	stmt, err := r.db.Prepare(`
 		INSERT INTO crawl_results (
-			html_version, url, page_title, heading_counts,
+			html_version, has_doctype, document_mode, charset, url, final_url, redirect_chain,
+			http_status_code, http_protocol, content_type, content_length, transfer_size, body_size,
+			dns_lookup_ms, connect_ms, tls_handshake_ms, ttfb_ms, download_ms, total_time_ms,
+			page_title,
+			meta_description, meta_keywords, canonical_url, meta_robots, viewport, language,
+			open_graph, twitter_card, favicon_url, heading_counts, multiple_h1, missing_h1, heading_level_skips,
 			internal_link_count, external_link_count, inaccessible_link_count,
-			has_login_form, error
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			image_count, images_missing_alt_count, images_empty_alt_count, images_missing_dimensions_count, broken_image_count,
+			accessibility_error_count, accessibility_warning_count,
+			active_mixed_content_count, passive_mixed_content_count, security_score, security_grade,
+			has_login_form, login_form_confidence, login_form_action, error, status, options,
+			session_id, depth
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return 0, fmt.Errorf("failed to prepare statement: %w", err)
@@ -49,14 +83,60 @@ func (r *mysqlCrawlResultRepository) Save(result domain.CrawlResult) (int, error
 
 	res, err := stmt.Exec(
 		result.HTMLVersion,
+		result.HasDoctype,
+		result.DocumentMode,
+		result.Charset,
 		result.URL.String,
+		result.FinalURL,
+		redirectChainJSON,
+		result.HTTPStatusCode,
+		result.HTTPProtocol,
+		result.ContentType,
+		result.ContentLength,
+		result.TransferSize,
+		result.BodySize,
+		result.DNSLookupMs,
+		result.ConnectMs,
+		result.TLSHandshakeMs,
+		result.TTFBMs,
+		result.DownloadMs,
+		result.TotalTimeMs,
 		result.PageTitle,
+		result.MetaDescription,
+		result.MetaKeywords,
+		result.CanonicalURL,
+		result.MetaRobots,
+		result.Viewport,
+		result.Language,
+		openGraphJSON,
+		twitterCardJSON,
+		result.FaviconURL,
 		headingCountsJSON,
+		result.MultipleH1,
+		result.MissingH1,
+		result.HeadingLevelSkips,
 		result.InternalLinkCount,
 		result.ExternalLinkCount,
 		result.InaccessibleLinkCount,
+		result.ImageCount,
+		result.ImagesMissingAltCount,
+		result.ImagesEmptyAltCount,
+		result.ImagesMissingDimensionsCount,
+		result.BrokenImageCount,
+		result.AccessibilityErrorCount,
+		result.AccessibilityWarningCount,
+		result.ActiveMixedContentCount,
+		result.PassiveMixedContentCount,
+		result.SecurityScore,
+		result.SecurityGrade,
 		result.HasLoginForm,
+		result.LoginFormConfidence,
+		result.LoginFormAction,
 		result.Error,
+		result.Status,
+		optionsJSON,
+		nullableID(result.SessionID),
+		result.Depth,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute statement: %w", err)
@@ -70,109 +150,519 @@ func (r *mysqlCrawlResultRepository) Save(result domain.CrawlResult) (int, error
 	return int(id), nil
 }
 
-// GetAll retrieves a paginated list of CrawlResults from the database
-func (r *mysqlCrawlResultRepository) GetAll(page, pageSize int, query, sortingJson string) ([]domain.CrawlResult, int, error) {
-	offset := (page - 1) * pageSize
+// Update overwrites the extracted fields and status of an existing CrawlResult
+func (r *mysqlCrawlResultRepository) Update(result domain.CrawlResult) error {
+	headingCountsJSON, err := json.Marshal(result.HeadingCounts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal heading counts: %w", err)
+	}
 
-	baseQuery := `
-		SELECT id, html_version, url, page_title, heading_counts,
-			internal_link_count, external_link_count, inaccessible_link_count,
-			has_login_form, error, created_at
+	redirectChainJSON, err := json.Marshal(result.RedirectChain)
+	if err != nil {
+		return fmt.Errorf("failed to marshal redirect chain: %w", err)
+	}
+
+	openGraphJSON, twitterCardJSON, err := marshalSocialMetadata(result)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`
+		UPDATE crawl_results SET
+			html_version = ?, has_doctype = ?, document_mode = ?, charset = ?, final_url = ?, redirect_chain = ?,
+			http_status_code = ?, http_protocol = ?, content_type = ?, content_length = ?, transfer_size = ?, body_size = ?,
+			dns_lookup_ms = ?, connect_ms = ?, tls_handshake_ms = ?, ttfb_ms = ?, download_ms = ?, total_time_ms = ?,
+			page_title = ?, meta_description = ?, meta_keywords = ?, canonical_url = ?, meta_robots = ?,
+			viewport = ?, language = ?, open_graph = ?, twitter_card = ?, favicon_url = ?, heading_counts = ?,
+			multiple_h1 = ?, missing_h1 = ?, heading_level_skips = ?,
+			internal_link_count = ?, external_link_count = ?, inaccessible_link_count = ?,
+			image_count = ?, images_missing_alt_count = ?, images_empty_alt_count = ?,
+			images_missing_dimensions_count = ?, broken_image_count = ?,
+			accessibility_error_count = ?, accessibility_warning_count = ?,
+			active_mixed_content_count = ?, passive_mixed_content_count = ?, security_score = ?, security_grade = ?,
+			has_login_form = ?, login_form_confidence = ?, login_form_action = ?, error = ?, status = ?
+		WHERE id = ?
+	`,
+		result.HTMLVersion,
+		result.HasDoctype,
+		result.DocumentMode,
+		result.Charset,
+		result.FinalURL,
+		redirectChainJSON,
+		result.HTTPStatusCode,
+		result.HTTPProtocol,
+		result.ContentType,
+		result.ContentLength,
+		result.TransferSize,
+		result.BodySize,
+		result.DNSLookupMs,
+		result.ConnectMs,
+		result.TLSHandshakeMs,
+		result.TTFBMs,
+		result.DownloadMs,
+		result.TotalTimeMs,
+		result.PageTitle,
+		result.MetaDescription,
+		result.MetaKeywords,
+		result.CanonicalURL,
+		result.MetaRobots,
+		result.Viewport,
+		result.Language,
+		openGraphJSON,
+		twitterCardJSON,
+		result.FaviconURL,
+		headingCountsJSON,
+		result.MultipleH1,
+		result.MissingH1,
+		result.HeadingLevelSkips,
+		result.InternalLinkCount,
+		result.ExternalLinkCount,
+		result.InaccessibleLinkCount,
+		result.ImageCount,
+		result.ImagesMissingAltCount,
+		result.ImagesEmptyAltCount,
+		result.ImagesMissingDimensionsCount,
+		result.BrokenImageCount,
+		result.AccessibilityErrorCount,
+		result.AccessibilityWarningCount,
+		result.ActiveMixedContentCount,
+		result.PassiveMixedContentCount,
+		result.SecurityScore,
+		result.SecurityGrade,
+		result.HasLoginForm,
+		result.LoginFormConfidence,
+		result.LoginFormAction,
+		result.Error,
+		result.Status,
+		result.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update crawl result: %w", err)
+	}
+	return nil
+}
+
+// CompareAndSetStatus changes the status of a CrawlResult only if its current status is one of from.
+// It reports whether the status was changed.
+func (r *mysqlCrawlResultRepository) CompareAndSetStatus(id int, from []domain.CrawlStatus, to domain.CrawlStatus) (bool, error) {
+	if len(from) == 0 {
+		return false, nil
+	}
+
+	placeholders := strings.Repeat("?, ", len(from)-1) + "?"
+	query := fmt.Sprintf("UPDATE crawl_results SET status = ? WHERE id = ? AND status IN (%s)", placeholders)
+
+	args := []interface{}{to, id}
+	for _, status := range from {
+		args = append(args, status)
+	}
+
+	res, err := r.db.Exec(query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to update crawl result status: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected for status update: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetByID retrieves a single CrawlResult from the database
+func (r *mysqlCrawlResultRepository) GetByID(id int) (domain.CrawlResult, error) {
+	row := r.db.QueryRow(`
+		SELECT `+crawlResultColumns+`
 		FROM crawl_results
-	`
+		WHERE id = ?
+	`, id)
 
-	var args []interface{}
-	whereClause := ""
+	result, err := scanCrawlResult(row)
+	if err == sql.ErrNoRows {
+		return domain.CrawlResult{}, domain.ErrCrawlResultNotFound
+	}
+	if err != nil {
+		return domain.CrawlResult{}, err
+	}
+	return result, nil
+}
 
-	if query != "" {
-		searchQuery := "%" + query + "%"
-		whereClause = " WHERE page_title LIKE ? OR url LIKE ?"
-		args = append(args, searchQuery, searchQuery)
+// GetByStatus retrieves every CrawlResult in one of the statuses, oldest first
+func (r *mysqlCrawlResultRepository) GetByStatus(statuses ...domain.CrawlStatus) ([]domain.CrawlResult, error) {
+	if len(statuses) == 0 {
+		return nil, nil
 	}
 
-	// Get total count first
-	totalCount, err := r.GetTotalCount(query, sortingJson)
+	args := make([]interface{}, len(statuses))
+	for i, status := range statuses {
+		args[i] = status
+	}
+	rows, err := r.db.Query(`
+		SELECT `+crawlResultColumns+`
+		FROM crawl_results
+		WHERE status IN (`+strings.Repeat("?, ", len(statuses)-1)+`?)
+		ORDER BY id
+	`, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get total count: %w", err)
+		return nil, fmt.Errorf("failed to query crawl results by status: %w", err)
 	}
+	defer rows.Close()
 
-	orderByClause, orderArgs, err := buildOrderByClause(sortingJson)
+	var results []domain.CrawlResult
+	for rows.Next() {
+		result, err := scanCrawlResult(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after scanning rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// GetBySessionID retrieves every CrawlResult stored under a CrawlSession, in crawl order
+func (r *mysqlCrawlResultRepository) GetBySessionID(sessionID int) ([]domain.CrawlResult, error) {
+	rows, err := r.db.Query(`
+		SELECT `+crawlResultColumns+`
+		FROM crawl_results
+		WHERE session_id = ?
+		ORDER BY depth, id
+	`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query crawl results of session: %w", err)
+	}
+	defer rows.Close()
+
+	results := []domain.CrawlResult{}
+	for rows.Next() {
+		result, err := scanCrawlResult(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after scanning rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// GetAll retrieves a page of CrawlResults from the database by offset.
+// Without sorting, the results of a full-text search are ordered by relevance.
+func (r *mysqlCrawlResultRepository) GetAll(page, pageSize int, query string, sorting []domain.SortField, filter domain.CrawlResultFilter) ([]domain.CrawlResult, error) {
+	offset := (page - 1) * pageSize
+
+	keys, err := resolveSortKeys(sorting, filter.Search != "")
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to build order by clause: %w", err)
+		return nil, err
 	}
+	fromClause, args, err := r.buildFromClause(filter)
+	if err != nil {
+		return nil, err
+	}
+	whereClause, whereArgs, err := buildWhereClause(query, filter)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, whereArgs...)
 
-	// Append ORDER BY, LIMIT, OFFSET for the main query
-	baseQuery += whereClause + orderByClause + `
+	baseQuery := `
+		SELECT ` + crawlResultColumns + `, ` + searchScoreColumn(filter) +
+		fromClause + whereClause + buildOrderByClause(keys, false) + `
 		LIMIT ? OFFSET ?
 	`
-	args = append(args, orderArgs...)
 	args = append(args, pageSize, offset)
 
 	// log.Printf("query: %s with args: %v", baseQuery, args)
 	rows, err := r.db.Query(baseQuery, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query crawl results: %w", err)
+		return nil, fmt.Errorf("failed to query crawl results: %w", err)
 	}
 	defer rows.Close()
 
 	var results []domain.CrawlResult
 	for rows.Next() {
-		var result domain.CrawlResult
-		var headingCountsJSON []byte
-
-		err := rows.Scan(
-			&result.ID,
-			&result.HTMLVersion,
-			&result.URL,
-			&result.PageTitle,
-			&headingCountsJSON,
-			&result.InternalLinkCount,
-			&result.ExternalLinkCount,
-			&result.InaccessibleLinkCount,
-			&result.HasLoginForm,
-			&result.Error,
-			&result.CreatedAt,
-		)
+		var searchScore float64
+		result, err := scanCrawlResult(rows, &searchScore)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan crawl result row: %w", err)
+			return nil, err
 		}
+		result.SearchScore = searchScore
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after scanning rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// GetPage retrieves the page of CrawlResults that follows the cursor position, or precedes it for a
+// backward cursor, by seeking on the sort keys instead of skipping rows. An empty cursor starts at the
+// beginning of the list. It returns the results with the cursors of the next and previous pages,
+// which are empty at either end of the list.
+func (r *mysqlCrawlResultRepository) GetPage(cursor string, pageSize int, query string, sorting []domain.SortField, filter domain.CrawlResultFilter) ([]domain.CrawlResult, string, string, error) {
+	keys, err := resolveSortKeys(sorting, filter.Search != "")
+	if err != nil {
+		return nil, "", "", err
+	}
+	fromClause, args, err := r.buildFromClause(filter)
+	if err != nil {
+		return nil, "", "", err
+	}
+	whereClause, whereArgs, err := buildWhereClause(query, filter)
+	if err != nil {
+		return nil, "", "", err
+	}
+	args = append(args, whereArgs...)
 
-		// Unmarshal HeadingCounts JSON
-		if len(headingCountsJSON) > 0 {
-			err = json.Unmarshal(headingCountsJSON, &result.HeadingCounts)
-			if err != nil {
-				return nil, 0, fmt.Errorf("failed to unmarshal heading counts JSON: %w", err)
-			}
+	var position pageCursor
+	if cursor != "" {
+		if position, err = decodeCursor(cursor, keys); err != nil {
+			return nil, "", "", err
 		}
+		condition, keysetArgs := keysetCondition(keys, position.Values, position.Backward)
+		if whereClause == "" {
+			whereClause = " WHERE " + condition
+		} else {
+			whereClause += " AND " + condition
+		}
+		args = append(args, keysetArgs...)
+	}
+
+	keyColumns := make([]string, len(keys))
+	for i, key := range keys {
+		keyColumns[i] = key.column.sql
+	}
+
+	// Fetch one row more than requested to learn whether another page follows
+	baseQuery := `
+		SELECT ` + crawlResultColumns + `, ` + searchScoreColumn(filter) + `, ` + strings.Join(keyColumns, ", ") +
+		fromClause + whereClause + buildOrderByClause(keys, position.Backward) + `
+		LIMIT ?
+	`
+	args = append(args, pageSize+1)
+
+	rows, err := r.db.Query(baseQuery, args...)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to query crawl results: %w", err)
+	}
+	defer rows.Close()
 
+	var results []domain.CrawlResult
+	var keyValues [][]interface{}
+	for rows.Next() {
+		var searchScore float64
+		destinations := sortKeyDestinations(keys)
+		result, err := scanCrawlResult(rows, append([]interface{}{&searchScore}, destinations...)...)
+		if err != nil {
+			return nil, "", "", err
+		}
+		result.SearchScore = searchScore
 		results = append(results, result)
+		keyValues = append(keyValues, sortKeyValues(destinations))
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, 0, fmt.Errorf("error after scanning rows: %w", err)
+		return nil, "", "", fmt.Errorf("error after scanning rows: %w", err)
+	}
+
+	hasMore := len(results) > pageSize
+	if hasMore {
+		results, keyValues = results[:pageSize], keyValues[:pageSize]
+	}
+	if len(results) == 0 {
+		return results, "", "", nil
+	}
+
+	// Backward pages are read in reverse order
+	if position.Backward {
+		slices.Reverse(results)
+		slices.Reverse(keyValues)
 	}
 
-	return results, totalCount, nil
+	nextCursor, prevCursor := pageCursors(keys, keyValues, cursor != "", position.Backward, hasMore)
+	return results, nextCursor, prevCursor, nil
 }
 
-// GetTotalCount retrieves the total number of crawl results from the database
-func (r *mysqlCrawlResultRepository) GetTotalCount(query, sortingJson string) (int, error) {
-	var count int
-	baseQuery := "SELECT COUNT(*) FROM crawl_results"
-	var args []interface{}
+// crawlResultColumns lists the crawl_results columns in the order scanCrawlResult expects
+const crawlResultColumns = `id, html_version, has_doctype, document_mode, charset, url, final_url, redirect_chain,
+			http_status_code, http_protocol, content_type, content_length, transfer_size, body_size,
+			dns_lookup_ms, connect_ms, tls_handshake_ms, ttfb_ms, download_ms, total_time_ms,
+			page_title,
+			meta_description, meta_keywords, canonical_url, meta_robots, viewport, language,
+			open_graph, twitter_card, favicon_url, heading_counts, multiple_h1, missing_h1, heading_level_skips,
+			internal_link_count, external_link_count, inaccessible_link_count,
+			image_count, images_missing_alt_count, images_empty_alt_count, images_missing_dimensions_count, broken_image_count,
+			accessibility_error_count, accessibility_warning_count,
+			active_mixed_content_count, passive_mixed_content_count, security_score, security_grade,
+			has_login_form, login_form_confidence, login_form_action, error, status, options, session_id, depth, created_at`
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
-	if query != "" {
-		searchQuery := "%" + query + "%"
-		baseQuery += " WHERE page_title LIKE ? OR url LIKE ?"
-		args = append(args, searchQuery, searchQuery)
+// scanCrawlResult scans a row selected with crawlResultColumns into a CrawlResult.
+// Columns selected after crawlResultColumns are scanned into extra.
+func scanCrawlResult(row rowScanner, extra ...interface{}) (domain.CrawlResult, error) {
+	var result domain.CrawlResult
+	var redirectChainJSON, openGraphJSON, twitterCardJSON, headingCountsJSON, optionsJSON []byte
+	var sessionID, securityScore sql.NullInt64
+
+	destinations := []interface{}{
+		&result.ID,
+		&result.HTMLVersion,
+		&result.HasDoctype,
+		&result.DocumentMode,
+		&result.Charset,
+		&result.URL,
+		&result.FinalURL,
+		&redirectChainJSON,
+		&result.HTTPStatusCode,
+		&result.HTTPProtocol,
+		&result.ContentType,
+		&result.ContentLength,
+		&result.TransferSize,
+		&result.BodySize,
+		&result.DNSLookupMs,
+		&result.ConnectMs,
+		&result.TLSHandshakeMs,
+		&result.TTFBMs,
+		&result.DownloadMs,
+		&result.TotalTimeMs,
+		&result.PageTitle,
+		&result.MetaDescription,
+		&result.MetaKeywords,
+		&result.CanonicalURL,
+		&result.MetaRobots,
+		&result.Viewport,
+		&result.Language,
+		&openGraphJSON,
+		&twitterCardJSON,
+		&result.FaviconURL,
+		&headingCountsJSON,
+		&result.MultipleH1,
+		&result.MissingH1,
+		&result.HeadingLevelSkips,
+		&result.InternalLinkCount,
+		&result.ExternalLinkCount,
+		&result.InaccessibleLinkCount,
+		&result.ImageCount,
+		&result.ImagesMissingAltCount,
+		&result.ImagesEmptyAltCount,
+		&result.ImagesMissingDimensionsCount,
+		&result.BrokenImageCount,
+		&result.AccessibilityErrorCount,
+		&result.AccessibilityWarningCount,
+		&result.ActiveMixedContentCount,
+		&result.PassiveMixedContentCount,
+		&securityScore,
+		&result.SecurityGrade,
+		&result.HasLoginForm,
+		&result.LoginFormConfidence,
+		&result.LoginFormAction,
+		&result.Error,
+		&result.Status,
+		&optionsJSON,
+		&sessionID,
+		&result.Depth,
+		&result.CreatedAt,
+	}
+	err := row.Scan(append(destinations, extra...)...)
+	if err == sql.ErrNoRows {
+		return result, err
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to scan crawl result row: %w", err)
+	}
+
+	result.SessionID = int(sessionID.Int64)
+	if securityScore.Valid {
+		score := int(securityScore.Int64)
+		result.SecurityScore = &score
 	}
 
-	orderByClause, orderArgs, err := buildOrderByClause(sortingJson)
+	// Unmarshal RedirectChain JSON
+	if len(redirectChainJSON) > 0 {
+		err = json.Unmarshal(redirectChainJSON, &result.RedirectChain)
+		if err != nil {
+			return result, fmt.Errorf("failed to unmarshal redirect chain JSON: %w", err)
+		}
+	}
+
+	// Unmarshal Open Graph and Twitter Card JSON
+	if len(openGraphJSON) > 0 {
+		err = json.Unmarshal(openGraphJSON, &result.OpenGraph)
+		if err != nil {
+			return result, fmt.Errorf("failed to unmarshal open graph JSON: %w", err)
+		}
+	}
+	if len(twitterCardJSON) > 0 {
+		err = json.Unmarshal(twitterCardJSON, &result.TwitterCard)
+		if err != nil {
+			return result, fmt.Errorf("failed to unmarshal twitter card JSON: %w", err)
+		}
+	}
+
+	// Unmarshal HeadingCounts JSON
+	if len(headingCountsJSON) > 0 {
+		err = json.Unmarshal(headingCountsJSON, &result.HeadingCounts)
+		if err != nil {
+			return result, fmt.Errorf("failed to unmarshal heading counts JSON: %w", err)
+		}
+	}
+
+	// Unmarshal Options JSON
+	if len(optionsJSON) > 0 {
+		err = json.Unmarshal(optionsJSON, &result.Options)
+		if err != nil {
+			return result, fmt.Errorf("failed to unmarshal crawl options JSON: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// marshalSocialMetadata converts the Open Graph and Twitter Card maps to JSON
+func marshalSocialMetadata(result domain.CrawlResult) ([]byte, []byte, error) {
+	openGraphJSON, err := json.Marshal(result.OpenGraph)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal open graph properties: %w", err)
+	}
+	twitterCardJSON, err := json.Marshal(result.TwitterCard)
 	if err != nil {
-		return 0, fmt.Errorf("failed to build order by clause for total count: %w", err)
+		return nil, nil, fmt.Errorf("failed to marshal twitter card properties: %w", err)
 	}
-	baseQuery += orderByClause // Add order by to count query if needed (though usually not for count)
-	args = append(args, orderArgs...)
+	return openGraphJSON, twitterCardJSON, nil
+}
+
+// nullableID stores a zero ID as NULL, for optional foreign keys
+func nullableID(id int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id != 0}
+}
+
+// GetTotalCount retrieves the total number of crawl results from the database
+func (r *mysqlCrawlResultRepository) GetTotalCount(query string, filter domain.CrawlResultFilter) (int, error) {
+	var count int
+	fromClause, args, err := r.buildFromClause(filter)
+	if err != nil {
+		return 0, err
+	}
+	whereClause, whereArgs, err := buildWhereClause(query, filter)
+	if err != nil {
+		return 0, err
+	}
+	args = append(args, whereArgs...)
+	baseQuery := "SELECT COUNT(*)" + fromClause + whereClause
 
 	err = r.db.QueryRow(baseQuery, args...).Scan(&count)
 	if err != nil {
@@ -181,34 +671,242 @@ func (r *mysqlCrawlResultRepository) GetTotalCount(query, sortingJson string) (i
 	return count, nil
 }
 
-// buildOrderByClause parses the sorting JSON and builds the ORDER BY clause
-func buildOrderByClause(sortingJson string) (string, []interface{}, error) {
-	orderByClause := " ORDER BY id DESC" // Default order
-	var orderArgs []interface{}
+// estimatedCountLimit caps the rows counted for an estimated total of a filtered list
+const estimatedCountLimit = 10000
 
-	if sortingJson == "" {
-		return orderByClause, orderArgs, nil
+// EstimateTotalCount returns a cheap total: the table statistics for an unfiltered list, or an exact
+// count stopped at estimatedCountLimit rows otherwise. It reports whether the total is exact.
+func (r *mysqlCrawlResultRepository) EstimateTotalCount(query string, filter domain.CrawlResultFilter) (int, bool, error) {
+	fromClause, args, err := r.buildFromClause(filter)
+	if err != nil {
+		return 0, false, err
 	}
+	whereClause, whereArgs, err := buildWhereClause(query, filter)
+	if err != nil {
+		return 0, false, err
+	}
+	args = append(args, whereArgs...)
 
-	var sortingMap map[string]bool
-	if err := json.Unmarshal([]byte(sortingJson), &sortingMap); err != nil {
-		return "", nil, fmt.Errorf("failed to unmarshal sorting JSON: %w", err)
+	var count int
+	if whereClause == "" && filter.Search == "" {
+		err = r.db.QueryRow(`
+			SELECT COALESCE(TABLE_ROWS, 0)
+			FROM information_schema.TABLES
+			WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'crawl_results'
+		`).Scan(&count)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to estimate count of crawl results: %w", err)
+		}
+		return count, false, nil
 	}
 
-	var orderByParts []string
-	for col, isAsc := range sortingMap {
-		if isAsc {
-			orderByParts = append(orderByParts, fmt.Sprintf("%s ASC", col))
-		} else {
-			orderByParts = append(orderByParts, fmt.Sprintf("%s DESC", col))
+	args = append(args, estimatedCountLimit)
+	err = r.db.QueryRow("SELECT COUNT(*) FROM (SELECT 1"+fromClause+whereClause+" LIMIT ?) AS capped", args...).Scan(&count)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to estimate count of crawl results: %w", err)
+	}
+	return count, count < estimatedCountLimit, nil
+}
+
+// buildFromClause builds the FROM clause of a list query. A full-text search of the filter joins the matches
+// of the search backend, which restricts the results to them and makes their relevance available as search_score.
+func (r *mysqlCrawlResultRepository) buildFromClause(filter domain.CrawlResultFilter) (string, []interface{}, error) {
+	if filter.Search == "" {
+		return " FROM crawl_results", nil, nil
+	}
+	join, args, err := r.searchBackend.Match(filter.Search)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to match search query: %w", err)
+	}
+	return " FROM crawl_results" + join, args, nil
+}
+
+// searchScoreColumn selects the relevance of a full-text search hit, or 0 without a search
+func searchScoreColumn(filter domain.CrawlResultFilter) string {
+	if filter.Search == "" {
+		return "0"
+	}
+	return "search_score"
+}
+
+// buildWhereClause builds the WHERE clause for the search query and filter
+func buildWhereClause(query string, filter domain.CrawlResultFilter) (string, []interface{}, error) {
+	var conditions []string
+	var args []interface{}
+
+	if query != "" {
+		searchQuery := "%" + query + "%"
+		conditions = append(conditions, "(page_title LIKE ? OR url LIKE ?)")
+		args = append(args, searchQuery, searchQuery)
+	}
+
+	if filter.HasMetaDescription != nil {
+		conditions = append(conditions, presenceCondition("meta_description <> ''", *filter.HasMetaDescription))
+	}
+	if filter.HasCanonical != nil {
+		conditions = append(conditions, presenceCondition("canonical_url <> ''", *filter.HasCanonical))
+	}
+	if filter.NoIndex != nil {
+		conditions = append(conditions, presenceCondition("(meta_robots LIKE '%noindex%' OR meta_robots LIKE '%none%')", *filter.NoIndex))
+	}
+	if filter.Language != "" {
+		conditions = append(conditions, "(language = ? OR language LIKE ?)")
+		args = append(args, filter.Language, filter.Language+"-%")
+	}
+	if filter.HasOpenGraph != nil {
+		conditions = append(conditions, presenceCondition("JSON_LENGTH(open_graph) > 0", *filter.HasOpenGraph))
+	}
+	if filter.HasTwitterCard != nil {
+		conditions = append(conditions, presenceCondition("JSON_LENGTH(twitter_card) > 0", *filter.HasTwitterCard))
+	}
+	if filter.HTTPStatusCode != 0 {
+		conditions = append(conditions, "http_status_code = ?")
+		args = append(args, filter.HTTPStatusCode)
+	}
+	if filter.HTTPProtocol != "" {
+		conditions = append(conditions, "http_protocol = ?")
+		args = append(args, filter.HTTPProtocol)
+	}
+	rangeFilters := []struct {
+		condition string
+		value     *int64
+	}{
+		{"ttfb_ms >= ?", filter.MinTTFBMs},
+		{"ttfb_ms <= ?", filter.MaxTTFBMs},
+		{"total_time_ms >= ?", filter.MinTotalTimeMs},
+		{"total_time_ms <= ?", filter.MaxTotalTimeMs},
+	}
+	for _, rangeFilter := range rangeFilters {
+		if rangeFilter.value != nil {
+			conditions = append(conditions, rangeFilter.condition)
+			args = append(args, *rangeFilter.value)
+		}
+	}
+
+	if filter.Expression != nil {
+		condition, expressionArgs, err := buildFilterExpression(*filter.Expression, time.Now())
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, condition)
+		args = append(args, expressionArgs...)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil, nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+// presenceCondition returns the condition itself, or its negation when present is false
+func presenceCondition(condition string, present bool) string {
+	if present {
+		return condition
+	}
+	return "NOT COALESCE(" + condition + ", FALSE)"
+}
+
+// sortColumn is a field that sort specifications and cursors may use
+type sortColumn struct {
+	sql  string // Column or SQL expression; interpolated, so only constants may be used
+	kind columnKind
+}
+
+// sortableColumns maps the fields accepted in a sort specification to their crawl_results columns.
+// Nullable columns are coalesced to a value that sorts first, as NULL does, so cursors can compare them.
+var sortableColumns = map[string]sortColumn{
+	"id":                              {"id", columnNumber},
+	"url":                             {"COALESCE(url, '')", columnText},
+	"final_url":                       {"final_url", columnText},
+	"status":                          {"status", columnText},
+	"page_title":                      {"page_title", columnText},
+	"html_version":                    {"html_version", columnText},
+	"language":                        {"language", columnText},
+	"error":                           {"error", columnText},
+	"created_at":                      {"created_at", columnTime},
+	"depth":                           {"depth", columnNumber},
+	"http_status_code":                {"http_status_code", columnNumber},
+	"http_protocol":                   {"http_protocol", columnText},
+	"content_length":                  {"content_length", columnNumber},
+	"transfer_size":                   {"transfer_size", columnNumber},
+	"body_size":                       {"body_size", columnNumber},
+	"dns_lookup_ms":                   {"dns_lookup_ms", columnNumber},
+	"connect_ms":                      {"connect_ms", columnNumber},
+	"tls_handshake_ms":                {"tls_handshake_ms", columnNumber},
+	"ttfb_ms":                         {"ttfb_ms", columnNumber},
+	"download_ms":                     {"download_ms", columnNumber},
+	"total_time_ms":                   {"total_time_ms", columnNumber},
+	"heading_level_skips":             {"heading_level_skips", columnNumber},
+	"internal_link_count":             {"internal_link_count", columnNumber},
+	"external_link_count":             {"external_link_count", columnNumber},
+	"inaccessible_link_count":         {"inaccessible_link_count", columnNumber},
+	"image_count":                     {"image_count", columnNumber},
+	"images_missing_alt_count":        {"images_missing_alt_count", columnNumber},
+	"images_empty_alt_count":          {"images_empty_alt_count", columnNumber},
+	"images_missing_dimensions_count": {"images_missing_dimensions_count", columnNumber},
+	"broken_image_count":              {"broken_image_count", columnNumber},
+	"accessibility_error_count":       {"accessibility_error_count", columnNumber},
+	"accessibility_warning_count":     {"accessibility_warning_count", columnNumber},
+	"active_mixed_content_count":      {"active_mixed_content_count", columnNumber},
+	"passive_mixed_content_count":     {"passive_mixed_content_count", columnNumber},
+	"security_score":                  {"COALESCE(security_score, -1)", columnNumber},
+	"has_login_form":                  {"has_login_form", columnBool},
+	"login_form_confidence":           {"login_form_confidence", columnNumber},
+}
+
+// sortKey is a resolved entry of a sort specification
+type sortKey struct {
+	field      string
+	column     sortColumn
+	descending bool
+}
+
+// searchScoreSortColumn orders the results of a full-text search by relevance; search_score is selected by SearchBackend.Match
+var searchScoreSortColumn = sortColumn{"search_score", columnNumber}
+
+// resolveSortKeys validates a sort specification and appends the id tiebreaker. Rows that tie on every
+// field are ordered by id in the direction of the first field, so pages never overlap.
+// Without a specification, results are ordered newest first, or by relevance when search is set.
+func resolveSortKeys(sorting []domain.SortField, search bool) ([]sortKey, error) {
+	if len(sorting) == 0 {
+		keys := []sortKey{{field: "id", column: sortableColumns["id"], descending: true}}
+		if search {
+			keys = append([]sortKey{{field: "search_score", column: searchScoreSortColumn, descending: true}}, keys...)
 		}
+		return keys, nil
 	}
 
-	if len(orderByParts) > 0 {
-		orderByClause = " ORDER BY " + strings.Join(orderByParts, ", ")
+	var keys []sortKey
+	seen := make(map[string]bool)
+	for _, field := range sorting {
+		column, ok := sortableColumns[field.Field]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown field %q", domain.ErrInvalidSorting, field.Field)
+		}
+		if seen[field.Field] {
+			return nil, fmt.Errorf("%w: field %q is listed more than once", domain.ErrInvalidSorting, field.Field)
+		}
+		seen[field.Field] = true
+		keys = append(keys, sortKey{field: field.Field, column: column, descending: field.Direction == domain.SortDescending})
 	}
 
-	return orderByClause, orderArgs, nil
+	if !seen["id"] {
+		keys = append(keys, sortKey{field: "id", column: sortableColumns["id"], descending: keys[0].descending})
+	}
+	return keys, nil
+}
+
+// buildOrderByClause builds the ORDER BY clause of the sort keys, or of their opposite order when reverse is set
+func buildOrderByClause(keys []sortKey, reverse bool) string {
+	var orderByParts []string
+	for _, key := range keys {
+		if key.descending != reverse {
+			orderByParts = append(orderByParts, key.column.sql+" DESC")
+		} else {
+			orderByParts = append(orderByParts, key.column.sql+" ASC")
+		}
+	}
+	return " ORDER BY " + strings.Join(orderByParts, ", ")
 }
 
 // DeleteMany deletes multiple CrawlResults from the database by IDs
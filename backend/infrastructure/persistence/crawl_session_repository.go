@@ -0,0 +1,92 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+
+	"backend/domain"
+)
+
+// CrawlSessionRepository defines the interface for storing CrawlSession
+type CrawlSessionRepository interface {
+	Create(session domain.CrawlSession) (int, error)
+	GetByID(id int) (domain.CrawlSession, error)
+	UpdateStatus(id int, status domain.CrawlStatus) error
+	ReplaceStatus(from, to domain.CrawlStatus) (int, error)
+}
+
+// mysqlCrawlSessionRepository implements CrawlSessionRepository for MySQL
+type mysqlCrawlSessionRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLCrawlSessionRepository creates a new MySQLCrawlSessionRepository
+func NewMySQLCrawlSessionRepository(db *sql.DB) CrawlSessionRepository {
+	return &mysqlCrawlSessionRepository{db: db}
+}
+
+// Create saves a new CrawlSession to the database
+func (r *mysqlCrawlSessionRepository) Create(session domain.CrawlSession) (int, error) {
+	res, err := r.db.Exec(`
+		INSERT INTO crawl_sessions (root_url, status, max_depth, max_pages)
+		VALUES (?, ?, ?, ?)
+	`, session.RootURL, session.Status, session.MaxDepth, session.MaxPages)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert crawl session: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	return int(id), nil
+}
+
+// GetByID retrieves a CrawlSession and the number of pages stored under it
+func (r *mysqlCrawlSessionRepository) GetByID(id int) (domain.CrawlSession, error) {
+	var session domain.CrawlSession
+	err := r.db.QueryRow(`
+		SELECT s.id, s.root_url, s.status, s.max_depth, s.max_pages, s.created_at,
+			(SELECT COUNT(*) FROM crawl_results WHERE session_id = s.id)
+		FROM crawl_sessions s
+		WHERE s.id = ?
+	`, id).Scan(
+		&session.ID,
+		&session.RootURL,
+		&session.Status,
+		&session.MaxDepth,
+		&session.MaxPages,
+		&session.CreatedAt,
+		&session.PageCount,
+	)
+	if err == sql.ErrNoRows {
+		return domain.CrawlSession{}, domain.ErrCrawlSessionNotFound
+	}
+	if err != nil {
+		return domain.CrawlSession{}, fmt.Errorf("failed to get crawl session: %w", err)
+	}
+	return session, nil
+}
+
+// UpdateStatus changes the lifecycle status of a CrawlSession
+func (r *mysqlCrawlSessionRepository) UpdateStatus(id int, status domain.CrawlStatus) error {
+	_, err := r.db.Exec("UPDATE crawl_sessions SET status = ? WHERE id = ?", status, id)
+	if err != nil {
+		return fmt.Errorf("failed to update crawl session status: %w", err)
+	}
+	return nil
+}
+
+// ReplaceStatus moves every CrawlSession in the from status to the to status and returns how many changed
+func (r *mysqlCrawlSessionRepository) ReplaceStatus(from, to domain.CrawlStatus) (int, error) {
+	res, err := r.db.Exec("UPDATE crawl_sessions SET status = ? WHERE status = ?", to, from)
+	if err != nil {
+		return 0, fmt.Errorf("failed to replace crawl session status: %w", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected for crawl session status: %w", err)
+	}
+	return int(rowsAffected), nil
+}
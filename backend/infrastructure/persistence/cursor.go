@@ -0,0 +1,146 @@
+package persistence
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/domain"
+)
+
+// pageCursor is the decoded form of an opaque keyset cursor: the sort key values of the row a page starts after
+type pageCursor struct {
+	Sorting  string        `json:"s"`           // Sort specification the cursor was created for
+	Backward bool          `json:"b,omitempty"` // Page towards the start of the list, i.e. the rows before the position
+	Values   []interface{} `json:"v"`           // One value per sort key, the id tiebreaker last
+}
+
+// sortSignature identifies a sort specification, e.g. "page_title:asc,id:asc"
+func sortSignature(keys []sortKey) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		direction := domain.SortAscending
+		if key.descending {
+			direction = domain.SortDescending
+		}
+		parts[i] = key.field + ":" + direction
+	}
+	return strings.Join(parts, ",")
+}
+
+func encodeCursor(keys []sortKey, values []interface{}, backward bool) string {
+	// Marshalling strings, numbers, booleans and times cannot fail
+	data, _ := json.Marshal(pageCursor{Sorting: sortSignature(keys), Backward: backward, Values: values})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a cursor and checks that it was created for the same sort keys
+func decodeCursor(raw string, keys []sortKey) (pageCursor, error) {
+	var cursor pageCursor
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return cursor, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidCursor)
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidCursor)
+	}
+	if cursor.Sorting != sortSignature(keys) || len(cursor.Values) != len(keys) {
+		return cursor, fmt.Errorf("%w: cursor was created for a different sorting", domain.ErrInvalidCursor)
+	}
+
+	// JSON has no time type, so times come back as RFC 3339 strings
+	for i, key := range keys {
+		ok := false
+		switch value := cursor.Values[i].(type) {
+		case string:
+			if key.column.kind == columnTime {
+				parsed, err := time.Parse(time.RFC3339Nano, value)
+				cursor.Values[i], ok = parsed, err == nil
+			} else {
+				ok = key.column.kind == columnText
+			}
+		case float64:
+			ok = key.column.kind == columnNumber
+		case bool:
+			ok = key.column.kind == columnBool
+		}
+		if !ok {
+			return cursor, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidCursor)
+		}
+	}
+	return cursor, nil
+}
+
+// pageCursors returns the cursors of the pages after and before a non-empty page, given the sort key values
+// of its rows in list order. hasMore reports whether a row beyond the page was found in the read direction.
+// A backward page was reached from the rows after it, and a forward page from the rows before it unless it is the first.
+func pageCursors(keys []sortKey, keyValues [][]interface{}, fromCursor, backward, hasMore bool) (string, string) {
+	var nextCursor, prevCursor string
+	if hasMore || backward {
+		nextCursor = encodeCursor(keys, keyValues[len(keyValues)-1], false)
+	}
+	if backward && hasMore || !backward && fromCursor {
+		prevCursor = encodeCursor(keys, keyValues[0], true)
+	}
+	return nextCursor, prevCursor
+}
+
+// keysetCondition selects the rows that come after the cursor values in the order of the keys,
+// or before them when backward is set: (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ...
+func keysetCondition(keys []sortKey, values []interface{}, backward bool) (string, []interface{}) {
+	var alternatives []string
+	var args []interface{}
+	for i, key := range keys {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, keys[j].column.sql+" = ?")
+			args = append(args, values[j])
+		}
+		op := " > ?"
+		if key.descending != backward {
+			op = " < ?"
+		}
+		parts = append(parts, key.column.sql+op)
+		args = append(args, values[i])
+		alternatives = append(alternatives, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
+
+// sortKeyDestinations returns scan destinations for the sort key columns selected after crawlResultColumns
+func sortKeyDestinations(keys []sortKey) []interface{} {
+	destinations := make([]interface{}, len(keys))
+	for i, key := range keys {
+		switch key.column.kind {
+		case columnNumber:
+			destinations[i] = new(float64)
+		case columnBool:
+			destinations[i] = new(bool)
+		case columnTime:
+			destinations[i] = new(time.Time)
+		default:
+			destinations[i] = new(string)
+		}
+	}
+	return destinations
+}
+
+// sortKeyValues dereferences the destinations filled by a scan
+func sortKeyValues(destinations []interface{}) []interface{} {
+	values := make([]interface{}, len(destinations))
+	for i, destination := range destinations {
+		switch typed := destination.(type) {
+		case *float64:
+			values[i] = *typed
+		case *bool:
+			values[i] = *typed
+		case *time.Time:
+			values[i] = *typed
+		case *string:
+			values[i] = *typed
+		}
+	}
+	return values
+}
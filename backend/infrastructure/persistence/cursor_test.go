@@ -0,0 +1,264 @@
+package persistence
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"backend/domain"
+)
+
+func mustResolveSortKeys(t *testing.T, sorting ...domain.SortField) []sortKey {
+	t.Helper()
+	keys, err := resolveSortKeys(sorting, false)
+	if err != nil {
+		t.Fatalf("resolveSortKeys(%v) returned error: %v", sorting, err)
+	}
+	return keys
+}
+
+func asc(field string) domain.SortField {
+	return domain.SortField{Field: field, Direction: domain.SortAscending}
+}
+
+func desc(field string) domain.SortField {
+	return domain.SortField{Field: field, Direction: domain.SortDescending}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	tests := []struct {
+		name     string
+		sorting  []domain.SortField
+		values   []interface{}
+		backward bool
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "default order",
+			values:   []interface{}{42.0},
+			wantSQL:  "((id < ?))",
+			wantArgs: []interface{}{42.0},
+		},
+		{
+			name:     "default order backward",
+			values:   []interface{}{42.0},
+			backward: true,
+			wantSQL:  "((id > ?))",
+			wantArgs: []interface{}{42.0},
+		},
+		{
+			name:     "ascending with id tiebreaker",
+			sorting:  []domain.SortField{asc("page_title")},
+			values:   []interface{}{"Home", 7.0},
+			wantSQL:  "((page_title > ?) OR (page_title = ? AND id > ?))",
+			wantArgs: []interface{}{"Home", "Home", 7.0},
+		},
+		{
+			name:     "descending with id tiebreaker",
+			sorting:  []domain.SortField{desc("depth")},
+			values:   []interface{}{2.0, 7.0},
+			wantSQL:  "((depth < ?) OR (depth = ? AND id < ?))",
+			wantArgs: []interface{}{2.0, 2.0, 7.0},
+		},
+		{
+			name:     "mixed directions",
+			sorting:  []domain.SortField{desc("http_status_code"), asc("url")},
+			values:   []interface{}{404.0, "https://example.com", 7.0},
+			wantSQL:  "((http_status_code < ?) OR (http_status_code = ? AND COALESCE(url, '') > ?) OR (http_status_code = ? AND COALESCE(url, '') = ? AND id < ?))",
+			wantArgs: []interface{}{404.0, 404.0, "https://example.com", 404.0, "https://example.com", 7.0},
+		},
+		{
+			name:     "mixed directions backward",
+			sorting:  []domain.SortField{desc("http_status_code"), asc("url")},
+			values:   []interface{}{404.0, "https://example.com", 7.0},
+			backward: true,
+			wantSQL:  "((http_status_code > ?) OR (http_status_code = ? AND COALESCE(url, '') < ?) OR (http_status_code = ? AND COALESCE(url, '') = ? AND id > ?))",
+			wantArgs: []interface{}{404.0, 404.0, "https://example.com", 404.0, "https://example.com", 7.0},
+		},
+		{
+			name:     "explicit id keeps its direction",
+			sorting:  []domain.SortField{desc("depth"), asc("id")},
+			values:   []interface{}{2.0, 7.0},
+			wantSQL:  "((depth < ?) OR (depth = ? AND id > ?))",
+			wantArgs: []interface{}{2.0, 2.0, 7.0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := mustResolveSortKeys(t, tt.sorting...)
+			gotSQL, gotArgs := keysetCondition(keys, tt.values, tt.backward)
+			if gotSQL != tt.wantSQL {
+				t.Errorf("SQL = %q, want %q", gotSQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("args = %#v, want %#v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2026, 3, 15, 12, 30, 45, 123456789, time.UTC)
+	tests := []struct {
+		name     string
+		sorting  []domain.SortField
+		values   []interface{}
+		backward bool
+	}{
+		{"default order", nil, []interface{}{42.0}, false},
+		{"backward", nil, []interface{}{42.0}, true},
+		{"text", []domain.SortField{asc("page_title")}, []interface{}{"Ünïcode \"title\"", 7.0}, false},
+		{"empty text", []domain.SortField{asc("url")}, []interface{}{"", 7.0}, true},
+		{"time keeps nanoseconds", []domain.SortField{desc("created_at")}, []interface{}{createdAt, 7.0}, false},
+		{"bool", []domain.SortField{desc("has_login_form")}, []interface{}{true, 7.0}, false},
+		{"mixed", []domain.SortField{desc("depth"), asc("created_at"), asc("page_title")}, []interface{}{3.0, createdAt, "Home", 7.0}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := mustResolveSortKeys(t, tt.sorting...)
+			raw := encodeCursor(keys, tt.values, tt.backward)
+			got, err := decodeCursor(raw, keys)
+			if err != nil {
+				t.Fatalf("decodeCursor returned error: %v", err)
+			}
+			if got.Backward != tt.backward {
+				t.Errorf("Backward = %v, want %v", got.Backward, tt.backward)
+			}
+			if !reflect.DeepEqual(got.Values, tt.values) {
+				t.Errorf("Values = %#v, want %#v", got.Values, tt.values)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorErrors(t *testing.T) {
+	titleKeys := mustResolveSortKeys(t, asc("page_title"))
+	encoded := func(json string) string { return base64.RawURLEncoding.EncodeToString([]byte(json)) }
+
+	tests := []struct {
+		name string
+		raw  string
+		keys []sortKey
+	}{
+		{"not base64", "!!!", titleKeys},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"s":"page_title:asc,id:asc","v":["a",1]}`)), titleKeys},
+		{"not json", encoded("not json"), titleKeys},
+		{"different field", encodeCursor(mustResolveSortKeys(t, asc("url")), []interface{}{"a", 1.0}, false), titleKeys},
+		{"different direction", encodeCursor(mustResolveSortKeys(t, desc("page_title")), []interface{}{"a", 1.0}, false), titleKeys},
+		{"default order reused for a sort", encodeCursor(mustResolveSortKeys(t), []interface{}{1.0}, false), titleKeys},
+		{"sort reused for default order", encodeCursor(titleKeys, []interface{}{"a", 1.0}, false), mustResolveSortKeys(t)},
+		{"too few values", encoded(`{"s":"page_title:asc,id:asc","v":["a"]}`), titleKeys},
+		{"too many values", encoded(`{"s":"page_title:asc,id:asc","v":["a",1,2]}`), titleKeys},
+		{"number for text", encoded(`{"s":"page_title:asc,id:asc","v":[1,1]}`), titleKeys},
+		{"text for number", encoded(`{"s":"page_title:asc,id:asc","v":["a","1"]}`), titleKeys},
+		{"null value", encoded(`{"s":"page_title:asc,id:asc","v":[null,1]}`), titleKeys},
+		{"invalid time", encoded(`{"s":"created_at:desc,id:desc","v":["yesterday",1]}`), mustResolveSortKeys(t, desc("created_at"))},
+		{"bool for time", encoded(`{"s":"created_at:desc,id:desc","v":[true,1]}`), mustResolveSortKeys(t, desc("created_at"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeCursor(tt.raw, tt.keys); !errors.Is(err, domain.ErrInvalidCursor) {
+				t.Errorf("decodeCursor(%q) error = %v, want ErrInvalidCursor", tt.raw, err)
+			}
+		})
+	}
+}
+
+func TestPageCursors(t *testing.T) {
+	keys := mustResolveSortKeys(t, asc("depth"))
+	keyValues := [][]interface{}{{1.0, 10.0}, {1.0, 11.0}, {2.0, 5.0}}
+	first := encodeCursor(keys, keyValues[0], true)
+	last := encodeCursor(keys, keyValues[len(keyValues)-1], false)
+
+	tests := []struct {
+		name       string
+		fromCursor bool
+		backward   bool
+		hasMore    bool
+		wantNext   string
+		wantPrev   string
+	}{
+		{"only page", false, false, false, "", ""},
+		{"first page", false, false, true, last, ""},
+		{"middle page forward", true, false, true, last, first},
+		{"last page forward", true, false, false, "", first},
+		{"middle page backward", true, true, true, last, first},
+		{"first page reached backward", true, true, false, last, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotNext, gotPrev := pageCursors(keys, keyValues, tt.fromCursor, tt.backward, tt.hasMore)
+			if gotNext != tt.wantNext {
+				t.Errorf("next cursor = %q, want %q", gotNext, tt.wantNext)
+			}
+			if gotPrev != tt.wantPrev {
+				t.Errorf("previous cursor = %q, want %q", gotPrev, tt.wantPrev)
+			}
+		})
+	}
+}
+
+func TestPageCursorsSeekPastTies(t *testing.T) {
+	// Rows tie on depth and are told apart by id; the cursors must carry both so no row is skipped or repeated
+	keys := mustResolveSortKeys(t, desc("depth"))
+	keyValues := [][]interface{}{{3.0, 20.0}, {3.0, 12.0}}
+
+	next, prev := pageCursors(keys, keyValues, true, false, true)
+
+	nextPosition, err := decodeCursor(next, keys)
+	if err != nil {
+		t.Fatalf("decodeCursor(next) returned error: %v", err)
+	}
+	gotSQL, gotArgs := keysetCondition(keys, nextPosition.Values, nextPosition.Backward)
+	if want := "((depth < ?) OR (depth = ? AND id < ?))"; gotSQL != want {
+		t.Errorf("next page SQL = %q, want %q", gotSQL, want)
+	}
+	if want := []interface{}{3.0, 3.0, 12.0}; !reflect.DeepEqual(gotArgs, want) {
+		t.Errorf("next page args = %#v, want %#v", gotArgs, want)
+	}
+
+	prevPosition, err := decodeCursor(prev, keys)
+	if err != nil {
+		t.Fatalf("decodeCursor(prev) returned error: %v", err)
+	}
+	gotSQL, gotArgs = keysetCondition(keys, prevPosition.Values, prevPosition.Backward)
+	if want := "((depth > ?) OR (depth = ? AND id > ?))"; gotSQL != want {
+		t.Errorf("previous page SQL = %q, want %q", gotSQL, want)
+	}
+	if want := []interface{}{3.0, 3.0, 20.0}; !reflect.DeepEqual(gotArgs, want) {
+		t.Errorf("previous page args = %#v, want %#v", gotArgs, want)
+	}
+	if got := buildOrderByClause(keys, prevPosition.Backward); got != buildOrderByClause(mustResolveSortKeys(t, asc("depth"), asc("id")), false) {
+		t.Errorf("previous page order = %q, want the reversed sort order", got)
+	}
+}
+
+func TestSearchDefaultOrder(t *testing.T) {
+	keys, err := resolveSortKeys(nil, true)
+	if err != nil {
+		t.Fatalf("resolveSortKeys returned error: %v", err)
+	}
+
+	gotSQL, gotArgs := keysetCondition(keys, []interface{}{1.5, 7.0}, false)
+	if want := "((search_score < ?) OR (search_score = ? AND id < ?))"; gotSQL != want {
+		t.Errorf("SQL = %q, want %q", gotSQL, want)
+	}
+	if want := []interface{}{1.5, 1.5, 7.0}; !reflect.DeepEqual(gotArgs, want) {
+		t.Errorf("args = %#v, want %#v", gotArgs, want)
+	}
+
+	// A cursor of the unsearched list cannot be used to page through search results, nor the other way around
+	if _, err := decodeCursor(encodeCursor(mustResolveSortKeys(t), []interface{}{7.0}, false), keys); !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Errorf("decodeCursor of a newest first cursor error = %v, want ErrInvalidCursor", err)
+	}
+	if _, err := decodeCursor(encodeCursor(keys, []interface{}{1.5, 7.0}, false), mustResolveSortKeys(t)); !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Errorf("decodeCursor of a relevance cursor error = %v, want ErrInvalidCursor", err)
+	}
+}
@@ -0,0 +1,187 @@
+package persistence
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/domain"
+)
+
+// columnKind decides how the value of a filter or sort field is parsed and which operators it supports
+type columnKind int
+
+const (
+	columnText columnKind = iota
+	columnNumber
+	columnBool
+	columnPresence // Boolean field backed by a condition, e.g. has_error
+	columnTime
+	columnStatus
+)
+
+// filterField is a field that filter expressions may compare against
+type filterField struct {
+	sql  string // Column or SQL expression; interpolated, so only constants may be used
+	kind columnKind
+}
+
+// urlHostSQL extracts the lowercased host of the url column, without port, path or query
+const urlHostSQL = `LOWER(SUBSTRING_INDEX(SUBSTRING_INDEX(SUBSTRING_INDEX(SUBSTRING_INDEX(url, '://', -1), '/', 1), '?', 1), ':', 1))`
+
+// filterFields maps the fields accepted in filter expressions to their SQL
+var filterFields = map[string]filterField{
+	"url":                         {"url", columnText},
+	"final_url":                   {"final_url", columnText},
+	"host":                        {urlHostSQL, columnText},
+	"page_title":                  {"page_title", columnText},
+	"html_version":                {"html_version", columnText},
+	"language":                    {"language", columnText},
+	"http_protocol":               {"http_protocol", columnText},
+	"content_type":                {"content_type", columnText},
+	"error":                       {"error", columnText},
+	"status":                      {"status", columnStatus},
+	"has_error":                   {"error <> ''", columnPresence},
+	"has_login_form":              {"has_login_form", columnBool},
+	"multiple_h1":                 {"multiple_h1", columnBool},
+	"missing_h1":                  {"missing_h1", columnBool},
+	"created_at":                  {"created_at", columnTime},
+	"depth":                       {"depth", columnNumber},
+	"http_status_code":            {"http_status_code", columnNumber},
+	"ttfb_ms":                     {"ttfb_ms", columnNumber},
+	"total_time_ms":               {"total_time_ms", columnNumber},
+	"internal_link_count":         {"internal_link_count", columnNumber},
+	"external_link_count":         {"external_link_count", columnNumber},
+	"inaccessible_link_count":     {"inaccessible_link_count", columnNumber},
+	"image_count":                 {"image_count", columnNumber},
+	"broken_image_count":          {"broken_image_count", columnNumber},
+	"accessibility_error_count":   {"accessibility_error_count", columnNumber},
+	"accessibility_warning_count": {"accessibility_warning_count", columnNumber},
+	"active_mixed_content_count":  {"active_mixed_content_count", columnNumber},
+	"passive_mixed_content_count": {"passive_mixed_content_count", columnNumber},
+	"security_score":              {"security_score", columnNumber},
+}
+
+// filterOperators lists the comparison operators each kind of field supports
+var filterOperators = map[columnKind][]string{
+	columnText:     {domain.FilterEq, domain.FilterNe, domain.FilterContains},
+	columnNumber:   {domain.FilterEq, domain.FilterNe, domain.FilterLt, domain.FilterLte, domain.FilterGt, domain.FilterGte},
+	columnBool:     {domain.FilterEq, domain.FilterNe},
+	columnPresence: {domain.FilterEq, domain.FilterNe},
+	columnTime:     {domain.FilterLt, domain.FilterLte, domain.FilterGt, domain.FilterGte},
+	columnStatus:   {domain.FilterEq, domain.FilterNe},
+}
+
+// buildFilterExpression translates a filter expression into a parameterized SQL condition.
+// Relative times such as -7d are resolved against now.
+func buildFilterExpression(expression domain.FilterExpression, now time.Time) (string, []interface{}, error) {
+	switch expression.Op {
+	case domain.FilterAnd, domain.FilterOr:
+		if len(expression.Operands) == 0 {
+			return "", nil, fmt.Errorf("%w: %s without operands", domain.ErrInvalidFilter, expression.Op)
+		}
+		var conditions []string
+		var args []interface{}
+		for _, operand := range expression.Operands {
+			condition, operandArgs, err := buildFilterExpression(operand, now)
+			if err != nil {
+				return "", nil, err
+			}
+			conditions = append(conditions, condition)
+			args = append(args, operandArgs...)
+		}
+		return "(" + strings.Join(conditions, " "+strings.ToUpper(expression.Op)+" ") + ")", args, nil
+	case domain.FilterNot:
+		if len(expression.Operands) != 1 {
+			return "", nil, fmt.Errorf("%w: not takes exactly one operand", domain.ErrInvalidFilter)
+		}
+		condition, args, err := buildFilterExpression(expression.Operands[0], now)
+		if err != nil {
+			return "", nil, err
+		}
+		return "NOT COALESCE(" + condition + ", FALSE)", args, nil
+	}
+
+	field, ok := filterFields[expression.Field]
+	if !ok {
+		return "", nil, fmt.Errorf("%w: unknown field %q", domain.ErrInvalidFilter, expression.Field)
+	}
+	supported := false
+	for _, op := range filterOperators[field.kind] {
+		supported = supported || op == expression.Op
+	}
+	if !supported {
+		return "", nil, fmt.Errorf("%w: operator %q is not supported for %s", domain.ErrInvalidFilter, expression.Op, expression.Field)
+	}
+
+	invalidValue := fmt.Errorf("%w: invalid value for %s: %q", domain.ErrInvalidFilter, expression.Field, expression.Value)
+	switch field.kind {
+	case columnText:
+		if expression.Op == domain.FilterContains {
+			return field.sql + " LIKE ?", []interface{}{"%" + escapeLike(expression.Value) + "%"}, nil
+		}
+		value := expression.Value
+		if expression.Field == "host" {
+			value = strings.ToLower(value)
+		}
+		return field.sql + " " + expression.Op + " ?", []interface{}{value}, nil
+	case columnNumber:
+		value, err := strconv.ParseFloat(expression.Value, 64)
+		if err != nil {
+			return "", nil, invalidValue
+		}
+		return field.sql + " " + expression.Op + " ?", []interface{}{value}, nil
+	case columnBool, columnPresence:
+		value, err := strconv.ParseBool(expression.Value)
+		if err != nil {
+			return "", nil, invalidValue
+		}
+		if expression.Op == domain.FilterNe {
+			value = !value
+		}
+		if field.kind == columnPresence {
+			return presenceCondition(field.sql, value), nil, nil
+		}
+		return field.sql + " = ?", []interface{}{value}, nil
+	case columnTime:
+		value, err := parseFilterTime(expression.Value, now)
+		if err != nil {
+			return "", nil, invalidValue
+		}
+		return field.sql + " " + expression.Op + " ?", []interface{}{value}, nil
+	case columnStatus:
+		switch status := domain.CrawlStatus(expression.Value); status {
+		case domain.CrawlStatusQueued, domain.CrawlStatusRunning, domain.CrawlStatusDone, domain.CrawlStatusError, domain.CrawlStatusCancelled:
+			return field.sql + " " + expression.Op + " ?", []interface{}{status}, nil
+		}
+		return "", nil, invalidValue
+	}
+	return "", nil, invalidValue
+}
+
+// parseFilterTime accepts RFC 3339 timestamps, dates such as 2026-01-31 and times relative to now such as -7d, -12h or -30m
+func parseFilterTime(value string, now time.Time) (time.Time, error) {
+	if strings.HasPrefix(value, "-") && len(value) > 2 {
+		amount, err := strconv.Atoi(value[1 : len(value)-1])
+		if err == nil && amount >= 0 {
+			switch value[len(value)-1] {
+			case 'd':
+				return now.AddDate(0, 0, -amount), nil
+			case 'h':
+				return now.Add(-time.Duration(amount) * time.Hour), nil
+			case 'm':
+				return now.Add(-time.Duration(amount) * time.Minute), nil
+			}
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+// escapeLike escapes the LIKE wildcards of a value that should match literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,127 @@
+package persistence
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/domain"
+)
+
+var filterNow = time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+
+func comparison(field, op, value string) domain.FilterExpression {
+	return domain.FilterExpression{Op: op, Field: field, Value: value}
+}
+
+func TestBuildFilterExpression(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression domain.FilterExpression
+		wantSQL    string
+		wantArgs   []interface{}
+	}{
+		{"text equals", comparison("page_title", domain.FilterEq, "Home"), "page_title = ?", []interface{}{"Home"}},
+		{"text not equals", comparison("url", domain.FilterNe, "https://example.com"), "url != ?", []interface{}{"https://example.com"}},
+		{"text contains escapes wildcards", comparison("page_title", domain.FilterContains, `50%_off\`), "page_title LIKE ?", []interface{}{`%50\%\_off\\%`}},
+		{"host is lowercased", comparison("host", domain.FilterEq, "Example.COM"), urlHostSQL + " = ?", []interface{}{"example.com"}},
+		{"number", comparison("depth", domain.FilterGte, "2"), "depth >= ?", []interface{}{2.0}},
+		{"fractional number", comparison("ttfb_ms", domain.FilterLt, "12.5"), "ttfb_ms < ?", []interface{}{12.5}},
+		{"bool", comparison("has_login_form", domain.FilterEq, "true"), "has_login_form = ?", []interface{}{true}},
+		{"bool not equals is negated", comparison("missing_h1", domain.FilterNe, "true"), "missing_h1 = ?", []interface{}{false}},
+		{"presence", comparison("has_error", domain.FilterEq, "true"), "error <> ''", nil},
+		{"presence negated", comparison("has_error", domain.FilterEq, "false"), "NOT COALESCE(error <> '', FALSE)", nil},
+		{"presence not equals", comparison("has_error", domain.FilterNe, "false"), "error <> ''", nil},
+		{"relative days", comparison("created_at", domain.FilterGte, "-7d"), "created_at >= ?", []interface{}{filterNow.AddDate(0, 0, -7)}},
+		{"relative hours", comparison("created_at", domain.FilterGt, "-12h"), "created_at > ?", []interface{}{filterNow.Add(-12 * time.Hour)}},
+		{"relative minutes", comparison("created_at", domain.FilterLt, "-30m"), "created_at < ?", []interface{}{filterNow.Add(-30 * time.Minute)}},
+		{"date", comparison("created_at", domain.FilterLte, "2026-01-31"), "created_at <= ?", []interface{}{time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)}},
+		{"timestamp", comparison("created_at", domain.FilterGt, "2026-01-31T08:30:00Z"), "created_at > ?", []interface{}{time.Date(2026, 1, 31, 8, 30, 0, 0, time.UTC)}},
+		{"status", comparison("status", domain.FilterNe, "done"), "status != ?", []interface{}{domain.CrawlStatusDone}},
+		{
+			"and",
+			domain.FilterExpression{Op: domain.FilterAnd, Operands: []domain.FilterExpression{
+				comparison("status", domain.FilterEq, "done"),
+				comparison("depth", domain.FilterGt, "0"),
+			}},
+			"(status = ? AND depth > ?)",
+			[]interface{}{domain.CrawlStatusDone, 0.0},
+		},
+		{
+			"nested or inside and keeps argument order",
+			domain.FilterExpression{Op: domain.FilterAnd, Operands: []domain.FilterExpression{
+				{Op: domain.FilterOr, Operands: []domain.FilterExpression{
+					comparison("page_title", domain.FilterEq, "a"),
+					comparison("page_title", domain.FilterEq, "b"),
+				}},
+				comparison("page_title", domain.FilterEq, "c"),
+			}},
+			"((page_title = ? OR page_title = ?) AND page_title = ?)",
+			[]interface{}{"a", "b", "c"},
+		},
+		{
+			"not treats null as false",
+			domain.FilterExpression{Op: domain.FilterNot, Operands: []domain.FilterExpression{
+				comparison("language", domain.FilterEq, "en"),
+			}},
+			"NOT COALESCE(language = ?, FALSE)",
+			[]interface{}{"en"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSQL, gotArgs, err := buildFilterExpression(tt.expression, filterNow)
+			if err != nil {
+				t.Fatalf("buildFilterExpression returned error: %v", err)
+			}
+			if gotSQL != tt.wantSQL {
+				t.Errorf("SQL = %q, want %q", gotSQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("args = %#v, want %#v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestBuildFilterExpressionErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression domain.FilterExpression
+		message    string
+	}{
+		{"unknown field", comparison("password", domain.FilterEq, "x"), `unknown field "password"`},
+		{"contains on a number", comparison("depth", domain.FilterContains, "1"), `operator "~" is not supported for depth`},
+		{"less than on text", comparison("url", domain.FilterLt, "x"), `operator "<" is not supported for url`},
+		{"equals on a time", comparison("created_at", domain.FilterEq, "2026-01-31"), `operator "=" is not supported for created_at`},
+		{"invalid number", comparison("depth", domain.FilterEq, "deep"), "invalid value for depth"},
+		{"invalid bool", comparison("has_login_form", domain.FilterEq, "yes please"), "invalid value for has_login_form"},
+		{"invalid time", comparison("created_at", domain.FilterGt, "yesterday"), "invalid value for created_at"},
+		{"invalid relative unit", comparison("created_at", domain.FilterGt, "-7w"), "invalid value for created_at"},
+		{"invalid status", comparison("status", domain.FilterEq, "finished"), "invalid value for status"},
+		{"and without operands", domain.FilterExpression{Op: domain.FilterAnd}, "and without operands"},
+		{"not with two operands", domain.FilterExpression{Op: domain.FilterNot, Operands: []domain.FilterExpression{
+			comparison("depth", domain.FilterEq, "1"),
+			comparison("depth", domain.FilterEq, "2"),
+		}}, "exactly one operand"},
+		{"error inside an operand", domain.FilterExpression{Op: domain.FilterOr, Operands: []domain.FilterExpression{
+			comparison("depth", domain.FilterEq, "1"),
+			comparison("nope", domain.FilterEq, "2"),
+		}}, `unknown field "nope"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSQL, _, err := buildFilterExpression(tt.expression, filterNow)
+			if !errors.Is(err, domain.ErrInvalidFilter) {
+				t.Fatalf("buildFilterExpression = %q, %v; want ErrInvalidFilter", gotSQL, err)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+
+	"backend/domain"
+)
+
+// HeadingRepository defines the interface for storing the heading outline of a CrawlResult
+type HeadingRepository interface {
+	ReplaceForResult(crawlResultID int, headings []domain.Heading) error
+	GetByResultID(crawlResultID int) ([]domain.Heading, error)
+}
+
+// mysqlHeadingRepository implements HeadingRepository for MySQL
+type mysqlHeadingRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLHeadingRepository creates a new MySQLHeadingRepository
+func NewMySQLHeadingRepository(db *sql.DB) HeadingRepository {
+	return &mysqlHeadingRepository{db: db}
+}
+
+// ReplaceForResult deletes the stored headings of a CrawlResult and saves the given ones instead
+func (r *mysqlHeadingRepository) ReplaceForResult(crawlResultID int, headings []domain.Heading) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows := make([][]interface{}, len(headings))
+	for i, heading := range headings {
+		rows[i] = []interface{}{heading.Level, heading.Text, heading.Position}
+	}
+	if err := replaceChildRows(tx, "crawl_headings", crawlResultID, []string{"level", "text", "position"}, rows); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit headings: %w", err)
+	}
+	return nil
+}
+
+// GetByResultID retrieves the heading outline of a CrawlResult in document order
+func (r *mysqlHeadingRepository) GetByResultID(crawlResultID int) ([]domain.Heading, error) {
+	rows, err := r.db.Query(`
+		SELECT id, crawl_result_id, level, text, position
+		FROM crawl_headings
+		WHERE crawl_result_id = ?
+		ORDER BY position
+	`, crawlResultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query headings: %w", err)
+	}
+	defer rows.Close()
+
+	headings := []domain.Heading{}
+	for rows.Next() {
+		var heading domain.Heading
+		if err := rows.Scan(&heading.ID, &heading.CrawlResultID, &heading.Level, &heading.Text, &heading.Position); err != nil {
+			return nil, fmt.Errorf("failed to scan heading row: %w", err)
+		}
+		headings = append(headings, heading)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after scanning rows: %w", err)
+	}
+
+	return headings, nil
+}
@@ -0,0 +1,99 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+
+	"backend/domain"
+)
+
+// ImageRepository defines the interface for storing the image inventory of a CrawlResult
+type ImageRepository interface {
+	ReplaceForResult(crawlResultID int, images []domain.Image) error
+	GetByResultID(crawlResultID int) ([]domain.Image, error)
+}
+
+// mysqlImageRepository implements ImageRepository for MySQL
+type mysqlImageRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLImageRepository creates a new MySQLImageRepository
+func NewMySQLImageRepository(db *sql.DB) ImageRepository {
+	return &mysqlImageRepository{db: db}
+}
+
+// ReplaceForResult deletes the stored images of a CrawlResult and saves the given ones instead
+func (r *mysqlImageRepository) ReplaceForResult(crawlResultID int, images []domain.Image) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows := make([][]interface{}, len(images))
+	for i, image := range images {
+		rows[i] = []interface{}{
+			image.URL, image.Element, image.Attribute, image.Descriptor, image.HasAlt, image.Alt,
+			image.Width, image.Height, image.StatusCode, image.ByteSize, image.Broken, image.Error,
+		}
+	}
+	columns := []string{
+		"url", "element", "attribute", "descriptor", "has_alt", "alt", "width", "height",
+		"status_code", "byte_size", "broken", "error",
+	}
+	if err := replaceChildRows(tx, "crawl_images", crawlResultID, columns, rows); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit images: %w", err)
+	}
+	return nil
+}
+
+// GetByResultID retrieves the images of a CrawlResult in page order
+func (r *mysqlImageRepository) GetByResultID(crawlResultID int) ([]domain.Image, error) {
+	rows, err := r.db.Query(`
+		SELECT id, crawl_result_id, url, element, attribute, descriptor, has_alt, alt, width, height,
+			status_code, byte_size, broken, error
+		FROM crawl_images
+		WHERE crawl_result_id = ?
+		ORDER BY id
+	`, crawlResultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query images: %w", err)
+	}
+	defer rows.Close()
+
+	images := []domain.Image{}
+	for rows.Next() {
+		var image domain.Image
+		err := rows.Scan(
+			&image.ID,
+			&image.CrawlResultID,
+			&image.URL,
+			&image.Element,
+			&image.Attribute,
+			&image.Descriptor,
+			&image.HasAlt,
+			&image.Alt,
+			&image.Width,
+			&image.Height,
+			&image.StatusCode,
+			&image.ByteSize,
+			&image.Broken,
+			&image.Error,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan image row: %w", err)
+		}
+		images = append(images, image)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after scanning rows: %w", err)
+	}
+
+	return images, nil
+}
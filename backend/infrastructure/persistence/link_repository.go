@@ -0,0 +1,119 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"backend/domain"
+)
+
+// LinkRepository defines the interface for storing the link inventory of a CrawlResult
+type LinkRepository interface {
+	ReplaceForResult(crawlResultID int, links []domain.Link) error
+	GetByResultID(crawlResultID int, page, pageSize int, filter domain.LinkFilter) ([]domain.Link, int, error)
+}
+
+// mysqlLinkRepository implements LinkRepository for MySQL
+type mysqlLinkRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLLinkRepository creates a new MySQLLinkRepository
+func NewMySQLLinkRepository(db *sql.DB) LinkRepository {
+	return &mysqlLinkRepository{db: db}
+}
+
+// ReplaceForResult deletes the stored links of a CrawlResult and saves the given ones instead
+func (r *mysqlLinkRepository) ReplaceForResult(crawlResultID int, links []domain.Link) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows := make([][]interface{}, len(links))
+	for i, link := range links {
+		rows[i] = []interface{}{link.URL, link.Href, link.AnchorText, link.Rel, link.Target, link.Scheme, link.Classification}
+	}
+	columns := []string{"url", "href", "anchor_text", "rel", "target", "scheme", "classification"}
+	if err := replaceChildRows(tx, "crawl_links", crawlResultID, columns, rows); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit links: %w", err)
+	}
+	return nil
+}
+
+// GetByResultID retrieves a paginated, filtered list of the links of a CrawlResult in page order
+func (r *mysqlLinkRepository) GetByResultID(crawlResultID int, page, pageSize int, filter domain.LinkFilter) ([]domain.Link, int, error) {
+	conditions := []string{"crawl_result_id = ?"}
+	args := []interface{}{crawlResultID}
+
+	if filter.Classification != "" {
+		conditions = append(conditions, "classification = ?")
+		args = append(args, filter.Classification)
+	}
+	if filter.Scheme != "" {
+		conditions = append(conditions, "scheme = ?")
+		args = append(args, filter.Scheme)
+	}
+	if filter.Rel != "" {
+		// rel is stored as space separated tokens
+		conditions = append(conditions, "CONCAT(' ', rel, ' ') LIKE ?")
+		args = append(args, "% "+filter.Rel+" %")
+	}
+	if filter.Query != "" {
+		searchQuery := "%" + filter.Query + "%"
+		conditions = append(conditions, "(url LIKE ? OR anchor_text LIKE ?)")
+		args = append(args, searchQuery, searchQuery)
+	}
+	whereClause := " WHERE " + strings.Join(conditions, " AND ")
+
+	var totalCount int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM crawl_links"+whereClause, args...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to get total count of links: %w", err)
+	}
+
+	query := `
+		SELECT id, crawl_result_id, url, href, anchor_text, rel, target, scheme, classification
+		FROM crawl_links` + whereClause + `
+		ORDER BY id
+		LIMIT ? OFFSET ?
+	`
+	args = append(args, pageSize, (page-1)*pageSize)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query links: %w", err)
+	}
+	defer rows.Close()
+
+	links := []domain.Link{}
+	for rows.Next() {
+		var link domain.Link
+		err := rows.Scan(
+			&link.ID,
+			&link.CrawlResultID,
+			&link.URL,
+			&link.Href,
+			&link.AnchorText,
+			&link.Rel,
+			&link.Target,
+			&link.Scheme,
+			&link.Classification,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan link row: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error after scanning rows: %w", err)
+	}
+
+	return links, totalCount, nil
+}
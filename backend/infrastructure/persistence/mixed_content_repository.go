@@ -0,0 +1,81 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+
+	"backend/domain"
+)
+
+// MixedContentRepository defines the interface for storing the mixed content of a CrawlResult
+type MixedContentRepository interface {
+	ReplaceForResult(crawlResultID int, items []domain.MixedContent) error
+	GetByResultID(crawlResultID int, contentType string) ([]domain.MixedContent, error)
+}
+
+// mysqlMixedContentRepository implements MixedContentRepository for MySQL
+type mysqlMixedContentRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLMixedContentRepository creates a new MySQLMixedContentRepository
+func NewMySQLMixedContentRepository(db *sql.DB) MixedContentRepository {
+	return &mysqlMixedContentRepository{db: db}
+}
+
+// ReplaceForResult deletes the stored mixed content of a CrawlResult and saves the given items instead
+func (r *mysqlMixedContentRepository) ReplaceForResult(crawlResultID int, items []domain.MixedContent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows := make([][]interface{}, len(items))
+	for i, item := range items {
+		rows[i] = []interface{}{item.URL, item.Element, item.Attribute, item.Type}
+	}
+	if err := replaceChildRows(tx, "crawl_mixed_content", crawlResultID, []string{"url", "element", "attribute", "type"}, rows); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit mixed content: %w", err)
+	}
+	return nil
+}
+
+// GetByResultID retrieves the mixed content of a CrawlResult, optionally only active or passive items
+func (r *mysqlMixedContentRepository) GetByResultID(crawlResultID int, contentType string) ([]domain.MixedContent, error) {
+	query := `
+		SELECT id, crawl_result_id, url, element, attribute, type
+		FROM crawl_mixed_content
+		WHERE crawl_result_id = ?`
+	args := []interface{}{crawlResultID}
+	if contentType != "" {
+		query += " AND type = ?"
+		args = append(args, contentType)
+	}
+	query += " ORDER BY id"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query mixed content: %w", err)
+	}
+	defer rows.Close()
+
+	items := []domain.MixedContent{}
+	for rows.Next() {
+		var item domain.MixedContent
+		if err := rows.Scan(&item.ID, &item.CrawlResultID, &item.URL, &item.Element, &item.Attribute, &item.Type); err != nil {
+			return nil, fmt.Errorf("failed to scan mixed content row: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after scanning rows: %w", err)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,320 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"backend/domain"
+)
+
+// SearchBackend maintains the text of crawled pages that full-text searches run over, matches and ranks
+// queries against it and excerpts it for hits.
+// Queries use the syntax of searchTerms: words, "quoted phrases", -excluded words and prefix* words.
+type SearchBackend interface {
+	Index(document domain.SearchDocument) error
+	Remove(crawlResultID int) error
+	Snippets(query string, crawlResultIDs []int) (map[int]string, error)
+	// Match returns a JOIN clause, with its arguments, that restricts crawl_results to the pages matching
+	// the query and selects their relevance as search_score, so the crawl result queries can filter,
+	// sort, page and count the hits like any other column
+	Match(query string) (string, []interface{}, error)
+}
+
+// mysqlSearchBackend implements SearchBackend with the InnoDB FULLTEXT indexes of crawl_search_documents
+type mysqlSearchBackend struct {
+	db *sql.DB
+}
+
+// NewMySQLSearchBackend creates a new MySQLSearchBackend
+func NewMySQLSearchBackend(db *sql.DB) SearchBackend {
+	return &mysqlSearchBackend{db: db}
+}
+
+// Index stores the document of a CrawlResult, replacing the previous one
+func (b *mysqlSearchBackend) Index(document domain.SearchDocument) error {
+	_, err := b.db.Exec(`
+		INSERT INTO crawl_search_documents (crawl_result_id, title, meta_description, headings, body)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE title = VALUES(title), meta_description = VALUES(meta_description),
+			headings = VALUES(headings), body = VALUES(body)
+	`, document.CrawlResultID, document.Title, document.MetaDescription, document.Headings, document.Body)
+	if err != nil {
+		return fmt.Errorf("failed to index search document: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the document of a CrawlResult from the index
+func (b *mysqlSearchBackend) Remove(crawlResultID int) error {
+	if _, err := b.db.Exec("DELETE FROM crawl_search_documents WHERE crawl_result_id = ?", crawlResultID); err != nil {
+		return fmt.Errorf("failed to remove search document: %w", err)
+	}
+	return nil
+}
+
+// Snippets builds a highlighted excerpt of each document for the query
+func (b *mysqlSearchBackend) Snippets(query string, crawlResultIDs []int) (map[int]string, error) {
+	snippets := make(map[int]string)
+	if len(crawlResultIDs) == 0 {
+		return snippets, nil
+	}
+
+	placeholders := strings.Repeat("?, ", len(crawlResultIDs)-1) + "?"
+	args := make([]interface{}, len(crawlResultIDs))
+	for i, id := range crawlResultIDs {
+		args[i] = id
+	}
+	rows, err := b.db.Query(fmt.Sprintf(`
+		SELECT crawl_result_id, title, meta_description, headings, body
+		FROM crawl_search_documents
+		WHERE crawl_result_id IN (%s)
+	`, placeholders), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query search documents: %w", err)
+	}
+	defer rows.Close()
+
+	terms := searchTerms(query)
+	for rows.Next() {
+		var document domain.SearchDocument
+		if err := rows.Scan(&document.CrawlResultID, &document.Title, &document.MetaDescription, &document.Headings, &document.Body); err != nil {
+			return nil, fmt.Errorf("failed to scan search document row: %w", err)
+		}
+		snippets[document.CrawlResultID] = buildSnippet(terms, document.Body, document.Headings, document.MetaDescription, document.Title)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after scanning rows: %w", err)
+	}
+
+	return snippets, nil
+}
+
+// Match joins crawl_results to the documents matching a full-text query, with their relevance as
+// search_score. Matches in the title count three times. A query without required terms matches nothing.
+func (b *mysqlSearchBackend) Match(query string) (string, []interface{}, error) {
+	booleanQuery := booleanModeQuery(searchTerms(query))
+	if booleanQuery == "" {
+		return " JOIN (SELECT 0 AS search_result_id, 0 AS search_score) AS search ON FALSE", nil, nil
+	}
+	return `
+		JOIN (
+			SELECT crawl_result_id AS search_result_id,
+				MATCH(title) AGAINST(? IN BOOLEAN MODE) * 2 +
+				MATCH(title, meta_description, headings, body) AGAINST(? IN BOOLEAN MODE) AS search_score
+			FROM crawl_search_documents
+			WHERE MATCH(title, meta_description, headings, body) AGAINST(? IN BOOLEAN MODE)
+		) AS search ON search.search_result_id = crawl_results.id
+	`, []interface{}{booleanQuery, booleanQuery, booleanQuery}, nil
+}
+
+// searchTerm is a word or phrase of a search query
+type searchTerm struct {
+	text     string // Lowercased; phrases contain spaces
+	phrase   bool
+	prefix   bool // Matches words starting with text, written word*
+	excluded bool // Must not occur, written -word
+}
+
+// searchTerms splits a query into words and "quoted phrases". Characters with a meaning in the MySQL
+// boolean syntax are treated as separators, so any input gives a valid query.
+func searchTerms(query string) []searchTerm {
+	var terms []searchTerm
+	for i, part := range strings.Split(query, `"`) {
+		// Odd parts are inside quotes; an unterminated quote runs to the end of the query
+		if i%2 == 1 {
+			if words := searchWords(part); len(words) > 0 {
+				terms = append(terms, searchTerm{text: strings.Join(words, " "), phrase: len(words) > 1})
+			}
+			continue
+		}
+		// A word with inner punctuation, e.g. e-mail, is searched as the phrase of its parts
+		for _, field := range strings.Fields(part) {
+			words := searchWords(field)
+			if len(words) == 0 {
+				continue
+			}
+			terms = append(terms, searchTerm{
+				text:     strings.Join(words, " "),
+				phrase:   len(words) > 1,
+				prefix:   len(words) == 1 && strings.HasSuffix(field, "*"),
+				excluded: strings.HasPrefix(field, "-"),
+			})
+		}
+	}
+	return terms
+}
+
+// searchWords lowercases text and splits it at anything but letters and digits, as the FULLTEXT parser does
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// booleanModeQuery requires every term, e.g. +crawler +"site map" -draft +analy*
+func booleanModeQuery(terms []searchTerm) string {
+	var parts []string
+	for _, term := range terms {
+		part := term.text
+		if term.phrase {
+			part = `"` + part + `"`
+		}
+		if term.prefix {
+			part += "*"
+		}
+		if term.excluded {
+			parts = append(parts, "-"+part)
+		} else {
+			parts = append(parts, "+"+part)
+		}
+	}
+	// A query of only exclusions matches nothing in boolean mode
+	for _, term := range terms {
+		if !term.excluded {
+			return strings.Join(parts, " ")
+		}
+	}
+	return ""
+}
+
+// snippetContext is the number of bytes of text shown on either side of the first match
+const snippetContext = 80
+
+// buildSnippet excerpts the first of the texts that contains a term around its first match,
+// HTML-escapes it and wraps the matches in <mark>. Without a match it starts the first non-empty text.
+func buildSnippet(terms []searchTerm, texts ...string) string {
+	for _, text := range texts {
+		if matches := findMatches(text, terms); len(matches) > 0 {
+			start, end := excerptBounds(text, matches[0][0], matches[0][1])
+			return highlight(text, start, end, matches)
+		}
+	}
+	for _, text := range texts {
+		if text != "" {
+			start, end := excerptBounds(text, 0, 0)
+			return highlight(text, start, end, nil)
+		}
+	}
+	return ""
+}
+
+// findMatches returns the sorted, non-overlapping byte ranges of text where a term occurs at a word start
+func findMatches(text string, terms []searchTerm) [][2]int {
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		return nil // Case folding changed byte offsets, which are needed to highlight the original text
+	}
+
+	var matches [][2]int
+	for i := 0; i < len(lower); {
+		matched := 0
+		if i == 0 || !isWordRune(lastRune(lower[:i])) {
+			for _, term := range terms {
+				if term.excluded {
+					continue
+				}
+				if n := matchTerm(lower[i:], term); n > matched {
+					matched = n
+				}
+			}
+		}
+		if matched > 0 {
+			matches = append(matches, [2]int{i, i + matched})
+			i += matched
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(lower[i:])
+		i += size
+	}
+	return matches
+}
+
+// matchTerm returns the length of the match of term at the start of text, or 0.
+// Phrase words may be separated by any non-word characters.
+func matchTerm(text string, term searchTerm) int {
+	pos := 0
+	for j, word := range strings.Split(term.text, " ") {
+		if j > 0 {
+			skipped := 0
+			for pos+skipped < len(text) {
+				r, size := utf8.DecodeRuneInString(text[pos+skipped:])
+				if isWordRune(r) {
+					break
+				}
+				skipped += size
+			}
+			if skipped == 0 {
+				return 0
+			}
+			pos += skipped
+		}
+		if !strings.HasPrefix(text[pos:], word) {
+			return 0
+		}
+		pos += len(word)
+	}
+
+	if term.prefix {
+		for pos < len(text) {
+			r, size := utf8.DecodeRuneInString(text[pos:])
+			if !isWordRune(r) {
+				break
+			}
+			pos += size
+		}
+	} else if pos < len(text) {
+		if r, _ := utf8.DecodeRuneInString(text[pos:]); isWordRune(r) {
+			return 0
+		}
+	}
+	return pos
+}
+
+// excerptBounds widens the range by snippetContext bytes on either side, moved to word boundaries
+func excerptBounds(text string, start, end int) (int, int) {
+	from := max(start-snippetContext, 0)
+	for from > 0 && from < start && text[from-1] != ' ' {
+		from++
+	}
+	to := min(end+snippetContext, len(text))
+	for to < len(text) && to > end && text[to] != ' ' {
+		to--
+	}
+	return from, to
+}
+
+// highlight HTML-escapes text[start:end] and wraps the matches within it in <mark>
+func highlight(text string, start, end int, matches [][2]int) string {
+	var snippet strings.Builder
+	if start > 0 {
+		snippet.WriteString("… ")
+	}
+	pos := start
+	for _, match := range matches {
+		if match[0] < pos || match[1] > end {
+			continue
+		}
+		snippet.WriteString(html.EscapeString(text[pos:match[0]]))
+		snippet.WriteString("<mark>" + html.EscapeString(text[match[0]:match[1]]) + "</mark>")
+		pos = match[1]
+	}
+	snippet.WriteString(html.EscapeString(text[pos:end]))
+	if end < len(text) {
+		snippet.WriteString(" …")
+	}
+	return strings.TrimSpace(snippet.String())
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func lastRune(text string) rune {
+	r, _ := utf8.DecodeLastRuneInString(text)
+	return r
+}
@@ -0,0 +1,134 @@
+package persistence
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"backend/domain"
+)
+
+// SecurityReportRepository defines the interface for storing the security report of a CrawlResult
+type SecurityReportRepository interface {
+	ReplaceForResult(crawlResultID int, report *domain.SecurityReport) error
+	GetByResultID(crawlResultID int) (domain.SecurityReport, error)
+	GetPreviousForURL(crawlResultID int) (*domain.SecurityReport, error)
+}
+
+// mysqlSecurityReportRepository implements SecurityReportRepository for MySQL
+type mysqlSecurityReportRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLSecurityReportRepository creates a new MySQLSecurityReportRepository
+func NewMySQLSecurityReportRepository(db *sql.DB) SecurityReportRepository {
+	return &mysqlSecurityReportRepository{db: db}
+}
+
+// ReplaceForResult deletes the stored report of a CrawlResult and saves the given one instead; a nil report only deletes
+func (r *mysqlSecurityReportRepository) ReplaceForResult(crawlResultID int, report *domain.SecurityReport) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM crawl_security_reports WHERE crawl_result_id = ?", crawlResultID); err != nil {
+		return fmt.Errorf("failed to delete security report: %w", err)
+	}
+
+	if report != nil {
+		tlsJSON, err := json.Marshal(report.TLS)
+		if err != nil {
+			return fmt.Errorf("failed to marshal TLS info: %w", err)
+		}
+		checksJSON, err := json.Marshal(report.Checks)
+		if err != nil {
+			return fmt.Errorf("failed to marshal security checks: %w", err)
+		}
+		cookiesJSON, err := json.Marshal(report.Cookies)
+		if err != nil {
+			return fmt.Errorf("failed to marshal cookie audits: %w", err)
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO crawl_security_reports (crawl_result_id, score, grade, tls, checks, cookies)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, crawlResultID, report.Score, report.Grade, tlsJSON, checksJSON, cookiesJSON)
+		if err != nil {
+			return fmt.Errorf("failed to insert security report: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit security report: %w", err)
+	}
+	return nil
+}
+
+// GetByResultID retrieves the security report of a CrawlResult
+func (r *mysqlSecurityReportRepository) GetByResultID(crawlResultID int) (domain.SecurityReport, error) {
+	row := r.db.QueryRow(`
+		SELECT `+securityReportColumns+`
+		FROM crawl_security_reports r
+		WHERE r.crawl_result_id = ?
+	`, crawlResultID)
+
+	report, err := scanSecurityReport(row)
+	if err == sql.ErrNoRows {
+		return domain.SecurityReport{}, domain.ErrSecurityReportNotFound
+	}
+	if err != nil {
+		return domain.SecurityReport{}, err
+	}
+	return report, nil
+}
+
+// GetPreviousForURL retrieves the latest report of an earlier crawl of the same URL, or nil if there is none
+func (r *mysqlSecurityReportRepository) GetPreviousForURL(crawlResultID int) (*domain.SecurityReport, error) {
+	row := r.db.QueryRow(`
+		SELECT `+securityReportColumns+`
+		FROM crawl_security_reports r
+		JOIN crawl_results c ON c.id = r.crawl_result_id
+		WHERE c.url = (SELECT url FROM crawl_results WHERE id = ?) AND r.crawl_result_id < ?
+		ORDER BY r.crawl_result_id DESC
+		LIMIT 1
+	`, crawlResultID, crawlResultID)
+
+	report, err := scanSecurityReport(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// securityReportColumns lists the crawl_security_reports columns in the order scanSecurityReport expects
+const securityReportColumns = `r.id, r.crawl_result_id, r.score, r.grade, r.tls, r.checks, r.cookies, r.created_at`
+
+// scanSecurityReport scans a row selected with securityReportColumns into a SecurityReport
+func scanSecurityReport(row rowScanner) (domain.SecurityReport, error) {
+	var report domain.SecurityReport
+	var tlsJSON, checksJSON, cookiesJSON []byte
+
+	err := row.Scan(&report.ID, &report.CrawlResultID, &report.Score, &report.Grade, &tlsJSON, &checksJSON, &cookiesJSON, &report.CreatedAt)
+	if err == sql.ErrNoRows {
+		return report, err
+	}
+	if err != nil {
+		return report, fmt.Errorf("failed to scan security report row: %w", err)
+	}
+
+	if err := json.Unmarshal(tlsJSON, &report.TLS); err != nil {
+		return report, fmt.Errorf("failed to unmarshal TLS info JSON: %w", err)
+	}
+	if err := json.Unmarshal(checksJSON, &report.Checks); err != nil {
+		return report, fmt.Errorf("failed to unmarshal security checks JSON: %w", err)
+	}
+	if err := json.Unmarshal(cookiesJSON, &report.Cookies); err != nil {
+		return report, fmt.Errorf("failed to unmarshal cookie audits JSON: %w", err)
+	}
+	return report, nil
+}
@@ -0,0 +1,121 @@
+package persistence
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"backend/domain"
+)
+
+// StructuredDataRepository defines the interface for storing the structured data of a CrawlResult
+type StructuredDataRepository interface {
+	ReplaceForResult(crawlResultID int, entities []domain.StructuredDataEntity, issues []domain.StructuredDataIssue) error
+	GetByResultID(crawlResultID int) ([]domain.StructuredDataEntity, []domain.StructuredDataIssue, error)
+}
+
+// mysqlStructuredDataRepository implements StructuredDataRepository for MySQL
+type mysqlStructuredDataRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLStructuredDataRepository creates a new MySQLStructuredDataRepository
+func NewMySQLStructuredDataRepository(db *sql.DB) StructuredDataRepository {
+	return &mysqlStructuredDataRepository{db: db}
+}
+
+// ReplaceForResult deletes the stored entities and issues of a CrawlResult and saves the given ones instead
+func (r *mysqlStructuredDataRepository) ReplaceForResult(crawlResultID int, entities []domain.StructuredDataEntity, issues []domain.StructuredDataIssue) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	entityRows := make([][]interface{}, len(entities))
+	for i, entity := range entities {
+		typesJSON, err := json.Marshal(entity.Types)
+		if err != nil {
+			return fmt.Errorf("failed to marshal structured data types: %w", err)
+		}
+		propertiesJSON, err := json.Marshal(entity.Properties)
+		if err != nil {
+			return fmt.Errorf("failed to marshal structured data properties: %w", err)
+		}
+		entityRows[i] = []interface{}{entity.Format, typesJSON, propertiesJSON}
+	}
+	if err := replaceChildRows(tx, "crawl_structured_data", crawlResultID, []string{"format", "types", "properties"}, entityRows); err != nil {
+		return err
+	}
+
+	issueRows := make([][]interface{}, len(issues))
+	for i, issue := range issues {
+		issueRows[i] = []interface{}{issue.Format, issue.Message}
+	}
+	if err := replaceChildRows(tx, "crawl_structured_data_issues", crawlResultID, []string{"format", "message"}, issueRows); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit structured data: %w", err)
+	}
+	return nil
+}
+
+// GetByResultID retrieves the structured data entities and issues of a CrawlResult
+func (r *mysqlStructuredDataRepository) GetByResultID(crawlResultID int) ([]domain.StructuredDataEntity, []domain.StructuredDataIssue, error) {
+	rows, err := r.db.Query(`
+		SELECT id, crawl_result_id, format, types, properties
+		FROM crawl_structured_data
+		WHERE crawl_result_id = ?
+		ORDER BY id
+	`, crawlResultID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query structured data: %w", err)
+	}
+	defer rows.Close()
+
+	entities := []domain.StructuredDataEntity{}
+	for rows.Next() {
+		var entity domain.StructuredDataEntity
+		var typesJSON, propertiesJSON []byte
+		if err := rows.Scan(&entity.ID, &entity.CrawlResultID, &entity.Format, &typesJSON, &propertiesJSON); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan structured data row: %w", err)
+		}
+		if err := json.Unmarshal(typesJSON, &entity.Types); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal structured data types JSON: %w", err)
+		}
+		if err := json.Unmarshal(propertiesJSON, &entity.Properties); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal structured data properties JSON: %w", err)
+		}
+		entities = append(entities, entity)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error after scanning rows: %w", err)
+	}
+
+	issueRows, err := r.db.Query(`
+		SELECT id, crawl_result_id, format, message
+		FROM crawl_structured_data_issues
+		WHERE crawl_result_id = ?
+		ORDER BY id
+	`, crawlResultID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query structured data issues: %w", err)
+	}
+	defer issueRows.Close()
+
+	issues := []domain.StructuredDataIssue{}
+	for issueRows.Next() {
+		var issue domain.StructuredDataIssue
+		if err := issueRows.Scan(&issue.ID, &issue.CrawlResultID, &issue.Format, &issue.Message); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan structured data issue row: %w", err)
+		}
+		issues = append(issues, issue)
+	}
+	if err = issueRows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error after scanning rows: %w", err)
+	}
+
+	return entities, issues, nil
+}
@@ -1,9 +1,15 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"os"
+	"strconv"
+	"time"
 
 	"backend/application/services"
+	"backend/domain"
 	"backend/handlers"
 	"backend/infrastructure/database"
 	"backend/infrastructure/persistence"
@@ -12,6 +18,26 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	crawlWorkerCount = 4    // Number of crawl jobs processed concurrently
+	crawlQueueSize   = 1000 // Maximum number of crawl jobs waiting to be processed, including site crawl pages
+
+	linkCheckConcurrency = 20               // Number of links checked concurrently per crawl
+	linkCheckPerHost     = 4                // Number of links checked concurrently against a single host
+	linkCheckTimeout     = 10 * time.Second // Timeout for a single link check
+
+	crawlerUserAgent = "SykellBot/1.0 (+https://github.com/jogunism/sykell)" // User agent sent with requests and matched against robots.txt
+)
+
+// defaultFetchOptions configures the HTTP client; crawl requests can override each setting
+var defaultFetchOptions = domain.FetchOptions{
+	ConnectTimeoutMs: 10000,
+	ReadTimeoutMs:    30000,
+	MaxBodyBytes:     10 << 20, // 10 MiB
+	MaxRedirects:     10,
+	UserAgent:        crawlerUserAgent,
+}
+
 func main() {
 	dbConnStr := "admin:HyunwooCho!23$@tcp(sykell.c10yg6egqxbv.eu-central-1.rds.amazonaws.com:3306)/sykell?parseTime=true"
 
@@ -22,13 +48,59 @@ func main() {
 	}
 	defer db.Close()
 
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("Failed to load schema migrations: %v", err)
+	}
+
+	// "migrate up|down [steps]|status" manages the schema without starting the server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCommand(migrator, os.Args[2:]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
+	// Apply pending schema migrations before the repositories use the tables
+	applied, err := migrator.Up(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to apply schema migrations: %v", err)
+	}
+	for _, migration := range applied {
+		log.Printf("Applied schema migration %d_%s", migration.Version, migration.Name)
+	}
 
 	// Initialize repositories
-	crawlResultRepo := persistence.NewMySQLCrawlResultRepository(db)
+	searchBackend := persistence.NewMySQLSearchBackend(db)
+	crawlResultRepo := persistence.NewMySQLCrawlResultRepository(db, searchBackend)
+	brokenLinkRepo := persistence.NewMySQLBrokenLinkRepository(db)
+	structuredDataRepo := persistence.NewMySQLStructuredDataRepository(db)
+	linkRepo := persistence.NewMySQLLinkRepository(db)
+	imageRepo := persistence.NewMySQLImageRepository(db)
+	a11yIssueRepo := persistence.NewMySQLAccessibilityIssueRepository(db)
+	headingRepo := persistence.NewMySQLHeadingRepository(db)
+	securityReportRepo := persistence.NewMySQLSecurityReportRepository(db)
+	mixedContentRepo := persistence.NewMySQLMixedContentRepository(db)
+	crawlSessionRepo := persistence.NewMySQLCrawlSessionRepository(db)
 
 	// Initialize services with their dependencies
 	testService := services.NewTestService()
-	crawlService := services.NewCrawlService(crawlResultRepo)
+	crawlQueue := services.NewCrawlQueue(crawlWorkerCount, crawlQueueSize)
+	fetcher := services.NewHTTPFetcher(defaultFetchOptions)
+	linkChecker := services.NewLinkChecker(fetcher, linkCheckConcurrency, linkCheckPerHost, linkCheckTimeout)
+	robotsChecker := services.NewRobotsChecker(fetcher, crawlerUserAgent)
+
+	// Register page analyzers; company-specific analyzers can be appended here
+	analyzers, err := services.NewAnalyzerRegistry(services.DefaultAnalyzers(linkChecker)...)
+	if err != nil {
+		log.Fatalf("Failed to register analyzers: %v", err)
+	}
+
+	crawlService := services.NewCrawlService(crawlResultRepo, brokenLinkRepo, structuredDataRepo, linkRepo, imageRepo, a11yIssueRepo, headingRepo, securityReportRepo, mixedContentRepo, searchBackend, crawlSessionRepo, crawlQueue, analyzers, robotsChecker, fetcher)
+
+	// Start the crawl worker pool
+	crawlService.Start()
+	defer crawlQueue.Stop()
 
 	// Initialize handlers with their respective services
 	testHandler := handlers.NewTestHandler(testService)
@@ -58,7 +130,63 @@ func main() {
 		protected.GET("/crawl/list", crawlHandler.GetCrawlResults)
 		protected.DELETE("/crawl", crawlHandler.DeleteCrawlResults)
 		protected.POST("/crawl", crawlHandler.Crawl)
+		protected.GET("/crawl/analyzers", crawlHandler.GetAnalyzers)
+		protected.GET("/crawl/sessions/:id", crawlHandler.GetCrawlSession)
+		protected.GET("/crawl/:id", crawlHandler.GetCrawlResult)
+		protected.GET("/crawl/:id/broken-links", crawlHandler.GetBrokenLinks)
+		protected.GET("/crawl/:id/structured-data", crawlHandler.GetStructuredData)
+		protected.GET("/crawl/:id/links", crawlHandler.GetLinks)
+		protected.GET("/crawl/:id/images", crawlHandler.GetImages)
+		protected.GET("/crawl/:id/accessibility-issues", crawlHandler.GetAccessibilityIssues)
+		protected.GET("/crawl/:id/headings", crawlHandler.GetHeadingOutline)
+		protected.GET("/crawl/:id/security", crawlHandler.GetSecurityReport)
+		protected.GET("/crawl/:id/mixed-content", crawlHandler.GetMixedContent)
+		protected.POST("/crawl/cancel", crawlHandler.CancelCrawls)
+		protected.POST("/crawl/requeue", crawlHandler.RequeueCrawls)
 	}
 
 	r.Run(":8080") // listen and serve on 0.0.0.0:8080
 }
+
+// runMigrateCommand applies, reverts or lists the schema migrations
+func runMigrateCommand(migrator *database.Migrator, args []string) error {
+	ctx := context.Background()
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate up|down [steps]|status")
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("Applied %d_%s\n", migration.Version, migration.Name)
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps: %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, migration := range reverted {
+			fmt.Printf("Reverted %d_%s\n", migration.Version, migration.Name)
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		for _, status := range statuses {
+			state := "pending"
+			if status.Dirty {
+				state = "dirty"
+			} else if status.Applied {
+				state = "applied"
+			}
+			fmt.Printf("%d_%s\t%s\n", status.Version, status.Name, state)
+		}
+		return err
+	}
+	return fmt.Errorf("unknown migrate command %q", args[0])
+}
@@ -1,7 +1,10 @@
 // constants
 
+export type CrawlStatus = "queued" | "running" | "done" | "error" | "cancelled";
+
 export interface CrawlItem {
   id: number;
+  status?: CrawlStatus;
   htmlVersion?: string;
   pageTitle?: string;
   headingCounts?: HeadingCounts;
@@ -9,11 +12,20 @@ export interface CrawlItem {
   externalLinkCount?: number;
   inaccessibleLinkCount?: number;
   hasLoginForm?: boolean;
+  loginFormConfidence?: number;
+  loginFormAction?: string;
   url?: string | null;
   error?: string;
   createdAt?: string;
 }
 
+export interface BrokenLink {
+  id: number;
+  url: string;
+  statusCode: number;
+  error: string;
+}
+
 export interface HeadingCounts {
   h1: number;
   h2: number;
@@ -9,10 +9,27 @@ import ArrowDown from "@icons/ArrowDown";
 // store
 import mainStore from "@store/mainStore";
 // constants
-import type { CrawlItem } from "@/constants";
+import type { CrawlItem, CrawlStatus } from "@/constants";
 // utils
 import { formatDate } from "@/utils";
 
+/**
+ * Status badge label and colors
+ */
+const statusBadge: {
+  [key in CrawlStatus]: { label: string; text: string; bg: string };
+} = {
+  queued: { label: "Queued", text: "text-gray-900", bg: "bg-gray-200" },
+  running: { label: "Running", text: "text-blue-900", bg: "bg-blue-200" },
+  done: { label: "Success", text: "text-green-900", bg: "bg-green-400" },
+  error: { label: "Failed", text: "text-red-900", bg: "bg-red-200" },
+  cancelled: {
+    label: "Cancelled",
+    text: "text-yellow-900",
+    bg: "bg-yellow-200",
+  },
+};
+
 /**
  * Component Home
  */
@@ -25,6 +42,7 @@ const Home: React.FC = () => {
     showDeleteButton,
     deleteCheckedItems,
     reAnalyticsUrls,
+    stopCheckedItems,
 
     checkedIds,
     setCheckedIds,
@@ -110,6 +128,12 @@ const Home: React.FC = () => {
                 >
                   Anaytics
                 </button>
+                <button
+                  className="w-[60px] h-10 bg-gray-500 hover:bg-gray-700 text-white text-sm font-bold py-2 px-2 rounded-md"
+                  onClick={stopCheckedItems}
+                >
+                  Stop
+                </button>
               </>
             )}
           </div>
@@ -147,10 +171,10 @@ const Home: React.FC = () => {
                 <div className="flex items-center justify-center">
                   <button
                     className="flex"
-                    onClick={() => toggleSorting("error")}
+                    onClick={() => toggleSorting("status")}
                   >
                     <span>Status</span>
-                    {sorting.error === false ? <ArrowDown /> : <ArrowUp />}
+                    {sorting.status === false ? <ArrowDown /> : <ArrowUp />}
                   </button>
                 </div>
               </th>
@@ -189,6 +213,8 @@ const Home: React.FC = () => {
             ) : (
               crawlItemList.map((item: CrawlItem) => {
                 const isSuccess: boolean = item.error === "";
+                const badge =
+                  statusBadge[item.status ?? (isSuccess ? "done" : "error")];
                 return (
                   <tr key={item.id}>
                     <td className="px-2 py-5 border-b bg-white text-sm">
@@ -256,19 +282,13 @@ const Home: React.FC = () => {
                     </td>
                     <td className="px-5 py-5 border-b border-gray-200 bg-white text-sm text-center">
                       <span
-                        className={`relative inline-block px-3 py-1 font-semibold leading-tight ${
-                          isSuccess ? "text-green-900" : "text-red-900"
-                        }`}
+                        className={`relative inline-block px-3 py-1 font-semibold leading-tight ${badge.text}`}
                       >
                         <span
                           aria-hidden
-                          className={`absolute inset-0 opacity-50 rounded-full ${
-                            isSuccess ? "bg-green-400" : "bg-red-200"
-                          }`}
+                          className={`absolute inset-0 opacity-50 rounded-full ${badge.bg}`}
                         />
-                        <span className="relative text-xs">
-                          {isSuccess ? "Success" : "Failed"}
-                        </span>
+                        <span className="relative text-xs">{badge.label}</span>
                       </span>
                     </td>
                     <td className="px-5 py-5 border-b border-gray-200 bg-white text-sm text-center hidden md:table-cell">
@@ -29,6 +29,7 @@ const HomeDetail: React.FC = () => {
     setModalOpen,
     headingChartData,
     linkChartData,
+    brokenLinks,
   } = mainStore();
 
   const COLORS = ["#0088FE", "#00C49F", "#FFBB28"]; // Colors for pie chart segments
@@ -99,7 +100,11 @@ const HomeDetail: React.FC = () => {
                     Has login form
                   </p>
                   <p className="text-sm font-medium text-gray-800 ">
-                    {currentItem?.hasLoginForm ? "YES" : "NO"}
+                    {currentItem?.hasLoginForm
+                      ? `YES (${Math.round(
+                          (currentItem?.loginFormConfidence ?? 0) * 100
+                        )}%)`
+                      : "NO"}
                   </p>
                 </div>
 
@@ -191,6 +196,33 @@ const HomeDetail: React.FC = () => {
             </div>
           </div>
         </div>
+
+        {brokenLinks.length > 0 && (
+          <div className="p-5 mb-6 border border-gray-300 rounded-2xl lg:p-6">
+            <h4 className="text-lg font-semibold text-gray-800 mb-4">
+              Broken Links
+            </h4>
+            {/* broken links */}
+            <ul className="divide-y divide-gray-200">
+              {brokenLinks.map((link) => (
+                <li key={link.id} className="py-2 text-sm">
+                  <a
+                    href={link.url}
+                    target="_blank"
+                    className="font-medium text-blue-800 break-all"
+                  >
+                    {link.url}
+                  </a>
+                  <p className="text-xs text-red-800">
+                    {link.statusCode > 0 && `${link.statusCode} `}
+                    {link.error}
+                  </p>
+                </li>
+              ))}
+            </ul>
+            {/* ./broken links */}
+          </div>
+        )}
       </div>
     </Modal>
   );
@@ -17,6 +17,15 @@ export const crawlList = async (
   } catch (error) {}
 };
 
+export const crawlBrokenLinks = async (id: number) => {
+  try {
+    const response = await http.get(`/crawl/${id}/broken-links`);
+    return snakeToCamel(response?.data);
+  } catch (error) {
+    throw error;
+  }
+};
+
 export const deleteCrawlItem = async (ids: number[]) => {
   try {
     const response = await http.delete("/crawl", { data: { ids: ids } });
@@ -26,6 +35,24 @@ export const deleteCrawlItem = async (ids: number[]) => {
   }
 };
 
+export const cancelCrawlItems = async (ids: number[]) => {
+  try {
+    const response = await http.post("/crawl/cancel", { ids: ids });
+    return response.data;
+  } catch (error) {
+    throw error;
+  }
+};
+
+export const requeueCrawlItems = async (ids: number[]) => {
+  try {
+    const response = await http.post("/crawl/requeue", { ids: ids });
+    return response.data;
+  } catch (error) {
+    throw error;
+  }
+};
+
 export const crawlUrl = async (url: string) => {
   try {
     const response = await http.post("/crawl", { url: url });
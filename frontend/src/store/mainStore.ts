@@ -1,13 +1,25 @@
 import React from "react";
 import { create } from "zustand";
 // APIs
-import { crawlList, deleteCrawlItem, crawlUrl } from "./api";
+import {
+  crawlList,
+  crawlBrokenLinks,
+  deleteCrawlItem,
+  cancelCrawlItems,
+  requeueCrawlItems,
+  crawlUrl,
+} from "./api";
 // Utils
 import { errorHandler } from "@lib/errorHandler";
 import { toast } from "@lib/toast";
 
 // constants
-import type { CrawlItem, HeadingChartItem, LinkChartItem } from "@/constants";
+import type {
+  BrokenLink,
+  CrawlItem,
+  HeadingChartItem,
+  LinkChartItem,
+} from "@/constants";
 
 /**
  * Main Store
@@ -35,6 +47,7 @@ interface MainStore {
   deleteCheckedItems: () => Promise<void>;
 
   reAnalyticsUrls: () => Promise<void>;
+  stopCheckedItems: () => Promise<void>;
 
   setCurrPage: (currPage: number) => Promise<void>;
   setQueryString: (query: string) => void;
@@ -46,6 +59,8 @@ interface MainStore {
 
   headingChartData: HeadingChartItem[];
   linkChartData: LinkChartItem[];
+  brokenLinks: BrokenLink[];
+  fetchBrokenLinks: (id: number) => Promise<void>;
 
   crawl: (url: string) => Promise<void>;
   reset: () => void;
@@ -81,6 +96,7 @@ const useMainStore = create<MainStore>((set, get) => ({
 
   headingChartData: [],
   linkChartData: [],
+  brokenLinks: [],
 
   /*************************************************************
    * State handlers
@@ -178,24 +194,9 @@ const useMainStore = create<MainStore>((set, get) => ({
   reAnalyticsUrls: async () => {
     set({ pending: true });
 
-    const itemMap = new Map(get().crawlItemList.map((item) => [item.id, item]));
-
     try {
-      const _promises = get()
-        .checkedIds.map((id) => {
-          const item = itemMap.get(id);
-          if (item?.url) {
-            return get().crawl(item.url);
-          }
-          console.warn(`NO url for ID ${id}. Skipping re-analysis.`);
-          return null;
-        })
-        .filter((p): p is Promise<void> => p !== null); // Type guard to filter out nulls
-
-      if (_promises.length > 0) {
-        toast.info(`${_promises.length} items submitted for re-analysis.`);
-        await Promise.all(_promises);
-      }
+      const response = await requeueCrawlItems(get().checkedIds);
+      toast.info(`${response.ids.length} items submitted for re-analysis.`);
 
       // Refresh the list to show updated data and clear selections
       await get().fetchCrawlList();
@@ -210,13 +211,34 @@ const useMainStore = create<MainStore>((set, get) => ({
     }
   },
 
+  stopCheckedItems: async () => {
+    set({ pending: true });
+
+    try {
+      const response = await cancelCrawlItems(get().checkedIds);
+      toast.success(response.message);
+
+      // Refresh the list to show updated data and clear selections
+      await get().fetchCrawlList();
+      get().setCheckedIds([]);
+    } catch (error) {
+      const err = errorHandler(error);
+      toast.error(err.message);
+    } finally {
+      set({ pending: false });
+    }
+  },
+
   setCurrentItem: (id?: number) => {
     const currItem = id
       ? get().crawlItemList.find((item: CrawlItem) => item.id === id) || null
       : null;
 
-    set({ currentItem: currItem });
+    set({ currentItem: currItem, brokenLinks: [] });
     if (currItem) {
+      if (currItem.inaccessibleLinkCount) {
+        get().fetchBrokenLinks(currItem.id);
+      }
       set({ isModalOpen: true });
       set({
         headingChartData: currItem.headingCounts
@@ -245,6 +267,16 @@ const useMainStore = create<MainStore>((set, get) => ({
     }
   },
 
+  fetchBrokenLinks: async (id: number) => {
+    try {
+      const response = await crawlBrokenLinks(id);
+      set({ brokenLinks: response.list ?? [] });
+    } catch (error) {
+      const err = errorHandler(error);
+      toast.error(err.message);
+    }
+  },
+
   setModalOpen: (isOpen: boolean) => {
     set({ isModalOpen: isOpen });
   },